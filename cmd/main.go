@@ -5,9 +5,11 @@ import (
 	"fmt"
 	mainserver "main-server"
 	config "main-server/config"
+	articleConstant "main-server/pkg/constant/article"
 	handler "main-server/pkg/handler"
 	repository "main-server/pkg/repository"
 	service "main-server/pkg/service"
+	authService "main-server/pkg/service/auth"
 	"os"
 	"os/signal"
 	"syscall"
@@ -137,24 +139,79 @@ func main() {
 	}
 
 	// Получение информации о всех правилах содержащихся в БД (синхронизация PERM модели и существующих данных в БД)
-	enforcer, err := casbin.NewEnforcer(viper.GetString("paths.perm_model"), adapter)
+	// (политика перезагружается в фоне, поэтому проверки прав и изменения политики синхронизируются)
+	enforcer, err := casbin.NewSyncedEnforcer(viper.GetString("paths.perm_model"), adapter)
 
 	if err != nil {
 		logrus.Fatalf("failed to initialize new enforcer: %s", err.Error())
 	}
 
+	// Политика хранится в памяти и перезагружается только при изменении таблицы правил (в том числе другими репликами)
+	policyWatcher, err := repository.NewPolicyWatcherPostgres(db, dns, viper.GetString("rules_table_name"))
+
+	if err != nil {
+		logrus.Fatalf("failed to initialize policy watcher: %s", err.Error())
+	}
+
+	enforcer.SetWatcher(policyWatcher)
+	policyWatcher.SetUpdateCallback(func(string) {
+		repository.ReloadPolicy(enforcer)
+	})
+
 	if err != nil {
 		logrus.Fatalf("failed to initialize db: %s", err.Error())
 	}
 
 	// Инициализация данных для доступа к внешним сервисам авторизации / регистрации
-	config.InitOAuth2Config()
+	if err := config.InitOAuth2ProvidersConfig(); err != nil {
+		logrus.Fatalf("failed to read configuration of oauth2 providers: %s", err.Error())
+	}
+
+	if err := authService.InitProviders(config.AppOAuth2ProvidersConfig); err != nil {
+		logrus.Fatalf("failed to initialize oauth2 providers: %s", err.Error())
+	}
+
+	// Токены провайдеров и секреты TOTP хранятся в БД только в зашифрованном виде
+	if err := authService.InitTokenCipher(viper.GetString("crypt.identity_key")); err != nil {
+		logrus.Warnf("tokens of oauth2 providers will not be stored and two-factor authentication is not available: %s", err.Error())
+	}
+
+	// Без надёжных ключей подписи токенов обновления, сброса пароля и второго фактора (HMAC) токены можно подделать
+	for _, name := range []string{"token.signing_key_refresh", "token.signing_key_reset", "token.signing_key_mfa"} {
+		if _, err := authService.HmacKey(viper.GetString(name)); err != nil {
+			logrus.Fatalf("invalid %s: %s", name, err.Error())
+		}
+	}
+
+	// Ключи подписи токенов доступа (несколько действующих ключей для плановой ротации)
+	if err := config.InitSigningKeysConfig(); err != nil {
+		logrus.Fatalf("failed to read configuration of signing keys: %s", err.Error())
+	}
+
+	if err := authService.InitKeyring(config.AppSigningKeysConfig); err != nil {
+		logrus.Fatalf("failed to initialize keyring of access tokens: %s", err.Error())
+	}
+
+	// Каждому провайдеру соответствует свой тип аутентификации пользователя
+	if err := repository.EnsureAuthTypes(db, authService.ProviderNames()); err != nil {
+		logrus.Fatalf("failed to create auth types of oauth2 providers: %s", err.Error())
+	}
 
 	// Реализация подхода dependency injection
 	repos := repository.NewRepository(db, enforcer)
 	service := service.NewService(repos)
 	handlers := handler.NewHandler(service)
 
+	// Период хранения удалённых статей в корзине
+	trashRetention := viper.GetDuration("articles.trash_retention")
+	if trashRetention <= 0 {
+		trashRetention = articleConstant.TRASH_RETENTION_DEFAULT
+	}
+
+	// Запуск планировщика публикации отложенных статей и очистки корзины
+	schedulerCtx, schedulerCancel := context.WithCancel(context.Background())
+	go service.Scheduler.Run(schedulerCtx, articleConstant.PUBLISH_SCHEDULER_INTERVAL, trashRetention)
+
 	// Создание нового экзепляра сервиса
 	srv := new(mainserver.Server)
 
@@ -174,6 +231,12 @@ func main() {
 
 	logrus.Print("MISU Main Server Shutting Down")
 
+	// Остановка планировщика статей
+	schedulerCancel()
+
+	// Остановка наблюдателя за таблицей правил
+	policyWatcher.Close()
+
 	// Освобождение ресурсов сервера
 	if err := srv.Shutdown(context.Background()); err != nil {
 		logrus.Errorf("error occured on server shutting down: %s", err.Error())
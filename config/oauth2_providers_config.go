@@ -0,0 +1,53 @@
+package config
+
+import (
+	authConstants "main-server/pkg/constant/auth"
+
+	"github.com/spf13/viper"
+)
+
+/*
+* Configuration of external OAuth2 / OpenID Connect provider
+* (name of provider in configuration is used as value of authentication type)
+ */
+type OAuth2ProviderConfig struct {
+	Type         string   `mapstructure:"type"`          // Реализация провайдера: google, vk, oidc
+	ClientId     string   `mapstructure:"client_id"`     // Идентификатор клиента
+	ClientSecret string   `mapstructure:"client_secret"` // Секрет клиента
+	RedirectUrl  string   `mapstructure:"redirect_url"`  // Адрес перенаправления после авторизации
+	Scopes       []string `mapstructure:"scopes"`        // Запрашиваемые разрешения (если не заданы - по умолчанию для типа)
+	Issuer       string   `mapstructure:"issuer"`        // Издатель OpenID Connect (для discovery)
+	AuthUrl      string   `mapstructure:"auth_url"`      // Переопределение адреса авторизации
+	TokenUrl     string   `mapstructure:"token_url"`     // Переопределение адреса получения токена
+	ApiUrl       string   `mapstructure:"api_url"`       // Переопределение адреса API провайдера
+}
+
+var AppOAuth2ProvidersConfig map[string]OAuth2ProviderConfig
+
+func InitOAuth2ProvidersConfig() error {
+	AppOAuth2ProvidersConfig = make(map[string]OAuth2ProviderConfig)
+
+	if viper.IsSet("oauth2_providers") {
+		return viper.UnmarshalKey("oauth2_providers", &AppOAuth2ProvidersConfig)
+	}
+
+	// Конфигурация прежнего формата (только Google и VK)
+	AppOAuth2ProvidersConfig[authConstants.AUTH_TYPE_GOOGLE] = OAuth2ProviderConfig{
+		Type:         authConstants.PROVIDER_TYPE_GOOGLE,
+		ClientId:     viper.GetString("oauth2.client_id"),
+		ClientSecret: viper.GetString("oauth2.client_secret"),
+		RedirectUrl:  viper.GetString("oauth2.redirect_url"),
+	}
+
+	AppOAuth2ProvidersConfig[authConstants.AUTH_TYPE_VK] = OAuth2ProviderConfig{
+		Type:         authConstants.PROVIDER_TYPE_VK,
+		ClientId:     viper.GetString("vk_oauth2.client_id"),
+		ClientSecret: viper.GetString("vk_oauth2.client_secret"),
+		RedirectUrl:  viper.GetString("vk_oauth2.redirect_url"),
+		AuthUrl:      viper.GetString("vk_oauth2.auth_url"),
+		TokenUrl:     viper.GetString("vk_oauth2.token_url"),
+		ApiUrl:       viper.GetString("vk_oauth2.api_url"),
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+/*
+* Checking that users can register themselves in domain (sign up and first sign in with provider)
+* (domains are listed in configuration, by default only the domain of server application is allowed)
+ */
+func IsSignUpDomain(value string) bool {
+	domains := viper.GetStringSlice("auth.sign_up_domains")
+	if len(domains) == 0 {
+		domains = []string{viper.GetString("domain")}
+	}
+
+	for _, domain := range domains {
+		if domain == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+/*
+* Configuration of asymmetric key, which is used for signing of access tokens
+* (RSA or Ed25519 private key in PEM format, key is published in JWKS until it expires)
+ */
+type SigningKeyConfig struct {
+	Kid        string `mapstructure:"kid"`         // Идентификатор ключа (заголовок kid токена)
+	Path       string `mapstructure:"path"`        // Путь к закрытому ключу в формате PEM
+	ActiveFrom string `mapstructure:"active_from"` // Время начала подписи токенов ключом (RFC 3339, пусто - сразу)
+	ExpiresAt  string `mapstructure:"expires_at"`  // Время, после которого ключ не принимается (RFC 3339, пусто - бессрочно)
+}
+
+var AppSigningKeysConfig []SigningKeyConfig
+
+func InitSigningKeysConfig() error {
+	AppSigningKeysConfig = make([]SigningKeyConfig, 0)
+
+	return viper.UnmarshalKey("token.signing_keys", &AppSigningKeysConfig)
+}
@@ -0,0 +1,42 @@
+package article
+
+import "time"
+
+const (
+	// Pagination of article lists
+	ARTICLES_LIMIT_DEFAULT = 20
+	ARTICLES_LIMIT_MAX     = 100
+
+	// Sort order of article lists (by creation date)
+	ORDER_ASC  = "asc"
+	ORDER_DESC = "desc"
+)
+
+const (
+	// Configuration of full-text search (Russian morphology)
+	SEARCH_CONFIG = "russian"
+
+	// Options for highlighting of found words in snippets
+	SEARCH_HEADLINE_OPTIONS = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+)
+
+const (
+	// Maximum length of normalized tag
+	TAG_MAX_LENGTH = 64
+)
+
+const (
+	// Statuses of article
+	STATUS_DRAFT     = "draft"
+	STATUS_REVIEW    = "review"
+	STATUS_PUBLISHED = "published"
+	STATUS_ARCHIVED  = "archived"
+)
+
+const (
+	// Interval of publishing of scheduled articles
+	PUBLISH_SCHEDULER_INTERVAL = time.Minute
+
+	// Default retention period of deleted articles in trash
+	TRASH_RETENTION_DEFAULT = 30 * 24 * time.Hour
+)
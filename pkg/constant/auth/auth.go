@@ -8,7 +8,86 @@ const (
 	TOKEN_TLL_ACCESS  = 1 * time.Hour
 	TOKEN_TLL_REFRESH = 12 * time.Hour
 	TOKEN_TLL_RESET   = 5 * time.Minute
+	TOKEN_TLL_MFA     = 5 * time.Minute
+
+	// Lifetime of activation link of account
+	TOKEN_TLL_ACTIVATION = 24 * time.Hour
+
+	// Time of caching of public keys of access tokens signing by other services
+	JWKS_CACHE_MAX_AGE = 5 * time.Minute
 
 	AUTH_TYPE_LOCAL  = "local"
 	AUTH_TYPE_GOOGLE = "google"
+	AUTH_TYPE_VK     = "vk"
+)
+
+const (
+	// Two-factor authentication (RFC 6238 TOTP)
+	MFA_ISSUER               = "МИСУ Мирный"
+	MFA_PERIOD               = 30
+	MFA_SKEW                 = 1
+	MFA_QR_SIZE              = 256
+	MFA_RECOVERY_CODES_COUNT = 10
+)
+
+const (
+	// Minimum length of secret keys of tokens signed by HMAC (256 bits for HS256)
+	HMAC_KEY_MIN_LENGTH = 32
+)
+
+const (
+	// Implementations of external authentication providers
+	PROVIDER_TYPE_GOOGLE = "google"
+	PROVIDER_TYPE_VK     = "vk"
+	PROVIDER_TYPE_OIDC   = "oidc"
+
+	// VK API version
+	VK_API_VERSION = "5.131"
+
+	// Timeout of requests to external authentication providers
+	PROVIDER_HTTP_TIMEOUT = 10 * time.Second
+
+	// State of authorization through provider is kept in cookie (protection of callback from login CSRF)
+	OAUTH2_STATE_COOKIE = "oauth2_state_"
+	OAUTH2_STATE_TTL    = 10 * time.Minute
+)
+
+const (
+	// Brute-force protection of sign-in, password recovery, resending of activation link and codes of second factor
+	ATTEMPTS_SCOPE_SIGN_IN  = "sign_in"
+	ATTEMPTS_SCOPE_RECOVERY = "recovery"
+	ATTEMPTS_SCOPE_ACTIVATE = "activate"
+	ATTEMPTS_SCOPE_MFA      = "mfa"
+	ATTEMPTS_KIND_ACCOUNT   = "account"
+	ATTEMPTS_KIND_IP        = "ip"
+
+	// Failed attempts are counted while they follow each other within the window
+	ATTEMPTS_WINDOW = 15 * time.Minute
+
+	// Maximum delay between attempts (delay is doubled after each failed attempt over free ones)
+	ATTEMPTS_DELAY_MAX = 5 * time.Minute
+
+	// Duration of temporary lockout (not less than window of attempts)
+	ATTEMPTS_LOCKOUT = 30 * time.Minute
+
+	SIGN_IN_DELAY_BASE     = 1 * time.Second
+	SIGN_IN_ACCOUNT_FREE   = 3
+	SIGN_IN_ACCOUNT_LIMIT  = 10
+	SIGN_IN_IP_FREE        = 10
+	SIGN_IN_IP_LIMIT       = 50
+	RECOVERY_DELAY_BASE    = 30 * time.Second
+	RECOVERY_ACCOUNT_FREE  = 1
+	RECOVERY_ACCOUNT_LIMIT = 5
+	RECOVERY_IP_FREE       = 5
+	RECOVERY_IP_LIMIT      = 20
+	ACTIVATE_DELAY_BASE    = 30 * time.Second
+	ACTIVATE_ACCOUNT_FREE  = 1
+	ACTIVATE_ACCOUNT_LIMIT = 5
+	ACTIVATE_IP_FREE       = 5
+	ACTIVATE_IP_LIMIT      = 20
+	MFA_DELAY_BASE         = 1 * time.Second
+	MFA_ACCOUNT_FREE       = 3
+	MFA_ACCOUNT_LIMIT      = 10
+	MFA_IP_FREE            = 10
+	MFA_IP_LIMIT           = 50
 )
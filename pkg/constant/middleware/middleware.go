@@ -2,9 +2,25 @@ package middleware
 
 const (
 	AUTHORIZATION_HEADER = "Authorization"
+	DOMAIN_HEADER        = "X-Domain"
+	DEVICE_HEADER        = "X-Device-Name"
 	USER_CTX             = "users_id"
 	AUTH_TYPE_VALUE_CTX  = "auth_type_value"
 	ACCESS_TOKEN_CTX     = "access_token"
 	TOKEN_API_CTX        = "token_api"
 	DOMAINS_ID           = "domains_id"
+	DOMAIN_CTX           = "domain"
+	SESSION_CTX          = "session"
+	TOKEN_ID_CTX         = "token_id"
+	OBJECT_CTX           = "object"
+)
+
+// Maximum size of request body, which is read by middleware for checking of permissions
+const ENFORCE_BODY_LIMIT = 10 << 20 // 10 MiB
+
+// Route groups, for which confirmation of email is required (configured by activation.required_groups)
+const (
+	ACTIVATION_GROUP_ARTICLE   = "article"
+	ACTIVATION_GROUP_MODERATOR = "moderator"
+	ACTIVATION_GROUP_ADMIN     = "admin"
 )
@@ -0,0 +1,18 @@
+package policy
+
+import "time"
+
+const (
+	// Channel of notifications about changes of rules table (Postgres LISTEN/NOTIFY)
+	POLICY_NOTIFY_CHANNEL = "casbin_rules"
+
+	// Trigger and function, which send notifications on change of rules table (schema/000018_rules_notify)
+	POLICY_NOTIFY_TRIGGER  = "casbin_rules_notify"
+	POLICY_NOTIFY_FUNCTION = "casbin_rules_notify"
+)
+
+const (
+	// Reconnection intervals of policy listener
+	POLICY_LISTENER_MIN_RECONNECT = 10 * time.Second
+	POLICY_LISTENER_MAX_RECONNECT = time.Minute
+)
@@ -0,0 +1,14 @@
+package route
+
+const (
+	ADMIN_MAIN_ROUTE   = "/admin"
+	ADMIN_DOMAIN_ROUTE = "/domain"
+	ADMIN_ROLE_ROUTE   = "/role"
+
+	ADMIN_ASSIGN_ROUTE = "/assign"
+	ADMIN_REMOVE_ROUTE = "/remove"
+	ADMIN_USERS_ROUTE  = "/users"
+	ADMIN_LOGOUT_ROUTE = "/logout"
+
+	ADMIN_METRICS_ROUTE = "/metrics"
+)
@@ -6,20 +6,29 @@ const (
 
 const (
 	// LOCAL
-	AUTH_SIGN_IN_ROUTE = "/sign-in"
-	AUTH_SIGN_UP_ROUTE = "/sign-up"
+	AUTH_SIGN_IN_ROUTE     = "/sign-in"
+	AUTH_SIGN_IN_MFA_ROUTE = "/sign-in/mfa"
+	AUTH_SIGN_UP_ROUTE     = "/sign-up"
 
 	// VK
-	AUTH_SIGN_IN_VK_ROUTE          = "/sign-in/vk"
-	AUTH_SIGN_IN_VK_CALLBACK_ROUTE = "/sign-in/vk/callback"
+	AUTH_SIGN_IN_VK_ROUTE           = "/sign-in/vk"
+	AUTH_SIGN_IN_VK_AUTHORIZE_ROUTE = "/sign-in/vk/authorize"
+	AUTH_SIGN_IN_VK_CALLBACK_ROUTE  = "/sign-in/vk/callback"
 
 	// Google
 	AUTH_SIGN_IN_GOOGLE_ROUTE = "/sign-in/oauth2"
 
+	// Any registered provider (Google, VK, OpenID Connect)
+	AUTH_SIGN_IN_PROVIDER_ROUTE           = "/sign-in/oauth2/:provider"
+	AUTH_SIGN_IN_PROVIDER_AUTHORIZE_ROUTE = "/sign-in/oauth2/:provider/authorize"
+	AUTH_SIGN_IN_PROVIDER_CALLBACK_ROUTE  = "/sign-in/oauth2/:provider/callback"
+
 	// MAIN
-	AUTH_REFRESH_TOKEN_ROUTE = "/refresh"
-	AUTH_LOGOUT_ROUTE        = "/logout"
-	AUTH_ACTIVATE_ROUTE      = "/activate/:link"
+	AUTH_REFRESH_TOKEN_ROUTE   = "/refresh"
+	AUTH_LOGOUT_ROUTE          = "/logout"
+	AUTH_ACTIVATE_ROUTE        = "/activate/:link"
+	AUTH_ACTIVATE_RESEND_ROUTE = "/activate/resend"
+	AUTH_UNLOCK_ROUTE          = "/unlock/:link"
 
 	// Password Recovery
 	AUTH_RECOVERY_PASSWORD = "/recovery/password"
@@ -7,4 +7,7 @@ const (
 	GET_ROUTE     = "/get"
 	GET_ALL_ROUTE = "/get/all"
 	DELETE_ROUTE  = "/delete"
+	SEARCH_ROUTE  = "/search"
+	DIFF_ROUTE    = "/diff"
+	RESTORE_ROUTE = "/restore"
 )
@@ -3,4 +3,5 @@ package route
 const (
 	GUEST_MAIN_ROUTE    = "/guest"
 	GUEST_ARTICLE_ROUTE = "/article"
+	GUEST_TAG_ROUTE     = "/tag"
 )
@@ -5,4 +5,9 @@ const (
 	MODERATOR_UNCHECKED_ROUTE = "/unchecked"
 
 	MODERATOR_ARTICLE_ROUTE = "/article"
+
+	MODERATOR_APPROVE_ROUTE = "/approve"
+	MODERATOR_REJECT_ROUTE  = "/reject"
+	MODERATOR_HISTORY_ROUTE = "/history"
+	MODERATOR_CHANGES_ROUTE = "/changes"
 )
@@ -9,7 +9,17 @@ const (
 
 	// Revoke token
 	OAUTH2_REVOKE_TOKEN_ROUTE = "https://oauth2.googleapis.com/revoke?token="
+)
+
+const (
+	// OpenID Connect discovery document (relative to issuer)
+	OIDC_DISCOVERY_ROUTE = "/.well-known/openid-configuration"
+)
 
-	// Refresh token
-	OAUTH2_REFRESH_TOKEN_ROUTE = "https://oauth2.googleapis.com/token?client_id="
+const (
+	// VK API (base URL may be overridden by api_url of provider)
+	VK_API_ROUTE = "https://api.vk.com/method"
+
+	// User info
+	VK_USERS_GET_METHOD = "/users.get"
 )
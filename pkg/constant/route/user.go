@@ -4,4 +4,25 @@ const (
 	USER_MAIN_ROUTE    = "/user"
 	USER_ARTICLE_ROUTE = "/article"
 	USER_PROFILE_ROUTE = "/profile"
+
+	USER_REVISION_ROUTE = "/revision"
+	USER_STATUS_ROUTE   = "/status"
+	USER_TRASH_ROUTE    = "/trash"
+	USER_SHARE_ROUTE    = "/share"
+	USER_GRANT_ROUTE    = "/grant"
+	USER_REVOKE_ROUTE   = "/revoke"
+
+	USER_SESSIONS_ROUTE      = "/sessions"
+	USER_REVOKE_OTHERS_ROUTE = "/revoke/others"
+
+	USER_MFA_ROUTE            = "/mfa"
+	USER_ENROLL_ROUTE         = "/enroll"
+	USER_CONFIRM_ROUTE        = "/confirm"
+	USER_DISABLE_ROUTE        = "/disable"
+	USER_RECOVERY_CODES_ROUTE = "/recovery-codes"
+
+	USER_AUTH_METHODS_ROUTE = "/auth-methods"
+	USER_LINK_ROUTE         = "/link/:provider"
+	USER_UNLINK_ROUTE       = "/unlink"
+	USER_PASSWORD_ROUTE     = "/password"
 )
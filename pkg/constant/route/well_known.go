@@ -0,0 +1,6 @@
+package route
+
+const (
+	WELL_KNOWN_MAIN_ROUTE = "/.well-known"
+	WELL_KNOWN_JWKS_ROUTE = "/jwks.json"
+)
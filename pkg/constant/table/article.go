@@ -1,8 +1,11 @@
 package table
 
 const (
-	ARTICLES_TABLE         = "articles"
-	FILES_TABLE            = "files"
-	ARTICLES_FILES_TABLE   = "articles_files"
-	ARTICLES_CHECKED_TABLE = "articles_checked"
+	ARTICLES_TABLE           = "articles"
+	FILES_TABLE              = "files"
+	ARTICLES_FILES_TABLE     = "articles_files"
+	ARTICLES_CHECKED_TABLE   = "articles_checked"
+	TAGS_TABLE               = "tags"
+	ARTICLES_TAGS_TABLE      = "articles_tags"
+	ARTICLES_REVISIONS_TABLE = "articles_revisions"
 )
@@ -1,16 +1,22 @@
 package table
 
 const (
-	USERS_TABLE            = "users"
-	USERS_DATA_TABLE       = "users_data"
-	ROLES_TABLE            = "roles"
-	ROLES_MODULES_TABLE    = "roles_modules"
-	ROLES_ATTRIBUTES_TABLE = "roles_attributes"
-	USERS_ROLES_TABLE      = "users_roles"
-	ACTIVATIONS_TABLE      = "activations"
-	TOKENS_TABLE           = "tokens"
-	RESET_TOKENS_TABLE     = "reset_tokens"
-	AUTH_TYPES_TABLE       = "auth_types"
-	USERS_AUTH_TYPES_TABLE = "users_auth_types"
-	SUPER_ADMINS_TABLE     = "super_admins"
+	USERS_TABLE               = "users"
+	USERS_DATA_TABLE          = "users_data"
+	ROLES_TABLE               = "roles"
+	ROLES_MODULES_TABLE       = "roles_modules"
+	ROLES_ATTRIBUTES_TABLE    = "roles_attributes"
+	USERS_ROLES_TABLE         = "users_roles"
+	ACTIVATIONS_TABLE         = "activations"
+	TOKENS_TABLE              = "tokens"
+	TOKENS_USED_TABLE         = "tokens_used"
+	REVOKED_TOKENS_TABLE      = "revoked_tokens"
+	AUTH_ATTEMPTS_TABLE       = "auth_attempts"
+	USERS_TOTP_TABLE          = "users_totp"
+	RECOVERY_CODES_TABLE      = "users_recovery_codes"
+	RESET_TOKENS_TABLE        = "reset_tokens"
+	AUTH_TYPES_TABLE          = "auth_types"
+	USERS_AUTH_TYPES_TABLE    = "users_auth_types"
+	EXTERNAL_IDENTITIES_TABLE = "external_identities"
+	SUPER_ADMINS_TABLE        = "super_admins"
 )
@@ -0,0 +1,408 @@
+package handler
+
+import (
+	rbacModel "main-server/pkg/model/rbac"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary CreateDomain
+// @Tags admin
+// @Description Создание домена
+// @ID create-domain
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.DomainCreateRequestModel true "credentials"
+// @Success 200 {object} rbacModel.DomainModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/domain/create [post]
+func (h *Handler) createDomain(c *gin.Context) {
+	var input rbacModel.DomainCreateRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.CreateDomain(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary UpdateDomain
+// @Tags admin
+// @Description Изменение домена
+// @ID update-domain
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.DomainUpdateRequestModel true "credentials"
+// @Success 200 {object} rbacModel.DomainModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/domain/update [post]
+func (h *Handler) updateDomain(c *gin.Context) {
+	var input rbacModel.DomainUpdateRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.UpdateDomain(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DeleteDomain
+// @Tags admin
+// @Description Удаление домена вместе с его ролями и политиками доступа
+// @ID delete-domain
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.DomainUuidModel true "credentials"
+// @Success 200 {object} rbacModel.RbacSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/domain/delete [post]
+func (h *Handler) deleteDomain(c *gin.Context) {
+	var input rbacModel.DomainUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.DeleteDomain(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetDomain
+// @Tags admin
+// @Description Получение домена
+// @ID get-domain
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.DomainUuidModel true "credentials"
+// @Success 200 {object} rbacModel.DomainModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/domain/get [post]
+func (h *Handler) getDomain(c *gin.Context) {
+	var input rbacModel.DomainUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.GetDomain(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetDomains
+// @Tags admin
+// @Description Получение списка доменов
+// @ID get-domains
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} rbacModel.DomainsModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/domain/get/all [post]
+func (h *Handler) getDomains(c *gin.Context) {
+	data, err := h.services.Admin.GetDomains(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary CreateRole
+// @Tags admin
+// @Description Создание роли в домене
+// @ID create-role
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.RoleCreateRequestModel true "credentials"
+// @Success 200 {object} rbacModel.RoleModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/role/create [post]
+func (h *Handler) createRole(c *gin.Context) {
+	var input rbacModel.RoleCreateRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.CreateRole(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary UpdateRole
+// @Tags admin
+// @Description Изменение роли
+// @ID update-role
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.RoleUpdateRequestModel true "credentials"
+// @Success 200 {object} rbacModel.RoleModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/role/update [post]
+func (h *Handler) updateRole(c *gin.Context) {
+	var input rbacModel.RoleUpdateRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.UpdateRole(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DeleteRole
+// @Tags admin
+// @Description Удаление роли вместе с её назначениями
+// @ID delete-role
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.RoleUuidModel true "credentials"
+// @Success 200 {object} rbacModel.RbacSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/role/delete [post]
+func (h *Handler) deleteRole(c *gin.Context) {
+	var input rbacModel.RoleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.DeleteRole(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetRole
+// @Tags admin
+// @Description Получение роли
+// @ID get-role
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.RoleUuidModel true "credentials"
+// @Success 200 {object} rbacModel.RoleModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/role/get [post]
+func (h *Handler) getRole(c *gin.Context) {
+	var input rbacModel.RoleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.GetRole(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetRoles
+// @Tags admin
+// @Description Получение списка ролей (всех или ролей домена)
+// @ID get-roles
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.RolesRequestModel false "credentials"
+// @Success 200 {object} rbacModel.RolesModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/role/get/all [post]
+func (h *Handler) getRoles(c *gin.Context) {
+	var input rbacModel.RolesRequestModel
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+			return
+		}
+	}
+
+	data, err := h.services.Admin.GetRoles(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary AssignRole
+// @Tags admin
+// @Description Назначение роли пользователю в домене роли
+// @ID assign-role
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.RoleAssignRequestModel true "credentials"
+// @Success 200 {object} rbacModel.RbacSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/role/assign [post]
+func (h *Handler) assignRole(c *gin.Context) {
+	var input rbacModel.RoleAssignRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.AssignRole(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary RemoveRole
+// @Tags admin
+// @Description Снятие роли с пользователя в домене роли
+// @ID remove-role
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.RoleAssignRequestModel true "credentials"
+// @Success 200 {object} rbacModel.RbacSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/role/remove [post]
+func (h *Handler) removeRole(c *gin.Context) {
+	var input rbacModel.RoleAssignRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.RemoveRole(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetRoleUsers
+// @Tags admin
+// @Description Получение списка пользователей, имеющих роль
+// @ID get-role-users
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.RoleUuidModel true "credentials"
+// @Success 200 {object} rbacModel.RoleUsersModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/role/users [post]
+func (h *Handler) getRoleUsers(c *gin.Context) {
+	var input rbacModel.RoleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.GetRoleUsers(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary ForceLogout
+// @Tags admin
+// @Description Принудительное завершение всех сессий пользователя (выданные токены доступа отзываются)
+// @ID force-logout
+// @Accept  json
+// @Produce  json
+// @Param input body rbacModel.UserUuidModel true "credentials"
+// @Success 200 {object} userModel.SessionRevokeModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/users/logout [post]
+func (h *Handler) forceLogout(c *gin.Context) {
+	var input rbacModel.UserUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Admin.ForceLogout(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
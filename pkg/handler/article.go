@@ -241,13 +241,55 @@ func (h *Handler) getArticle(c *gin.Context) {
 // @ID get-articles
 // @Accept  json
 // @Produce  json
+// @Param input body articleModel.ArticlesFilterModel false "filter"
 // @Success 200 {object} articleModel.ArticlesModel "data"
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /user/article/get/all [post]
 func (h *Handler) getArticles(c *gin.Context) {
-	data, err := h.services.User.GetArticles(c)
+	var input articleModel.ArticlesFilterModel
+
+	if err := bindArticlesFilter(c, &input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.GetArticles(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary SearchArticles
+// @Tags article
+// @Description Полнотекстовый поиск по статьям пользователя
+// @ID search-articles
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticlesSearchRequestModel true "search"
+// @Success 200 {object} articleModel.ArticlesSearchModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/search [post]
+func (h *Handler) searchArticles(c *gin.Context) {
+	var input articleModel.ArticlesSearchRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	if strings.TrimSpace(input.Query) == "" {
+		newErrorResponse(c, http.StatusBadRequest, "Пустой поисковый запрос!")
+		return
+	}
+
+	data, err := h.services.User.SearchArticles(c, input)
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -258,7 +300,7 @@ func (h *Handler) getArticles(c *gin.Context) {
 
 // @Summary DeleteArticle
 // @Tags article
-// @Description Удаление статьи
+// @Description Удаление статьи (перемещение в корзину)
 // @ID delete-article
 // @Accept  json
 // @Produce  json
@@ -284,3 +326,99 @@ func (h *Handler) deleteArticle(c *gin.Context) {
 
 	c.JSON(http.StatusOK, data)
 }
+
+/* Parse parameters of article list (the request body is optional) */
+func bindArticlesFilter(c *gin.Context, input *articleModel.ArticlesFilterModel) error {
+	if c.Request.ContentLength == 0 {
+		return nil
+	}
+
+	return c.ShouldBindJSON(input)
+}
+
+// @Summary UpdateArticleStatus
+// @Tags article
+// @Description Изменение статуса статьи (черновик, на проверке, в архиве) и времени её публикации
+// @ID update-article-status
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleStatusRequestModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/status [post]
+func (h *Handler) updateArticleStatus(c *gin.Context) {
+	var input articleModel.ArticleStatusRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.UpdateArticleStatus(input, c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetTrashArticles
+// @Tags article
+// @Description Получение списка статей, находящихся в корзине
+// @ID get-trash-articles
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticlesFilterModel false "filter"
+// @Success 200 {object} articleModel.ArticlesModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/trash/get/all [post]
+func (h *Handler) getTrashArticles(c *gin.Context) {
+	var input articleModel.ArticlesFilterModel
+
+	if err := bindArticlesFilter(c, &input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.GetTrashArticles(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary RestoreArticle
+// @Tags article
+// @Description Восстановление статьи из корзины
+// @ID restore-article
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleUuidModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/trash/restore [post]
+func (h *Handler) restoreArticle(c *gin.Context) {
+	var input articleModel.ArticleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.RestoreArticle(input, c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
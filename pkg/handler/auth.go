@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"crypto/subtle"
 	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
 	middlewareConstants "main-server/pkg/constant/middleware"
 	userModel "main-server/pkg/model/user"
+	authService "main-server/pkg/service/auth"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,13 +15,13 @@ import (
 
 // @Summary SignUp
 // @Tags auth
-// @Description Регистрация пользователя
+// @Description Регистрация пользователя (доступна только в доменах, разрешённых конфигурацией)
 // @ID create-account
 // @Accept  json
 // @Produce  json
 // @Param input body userModel.UserRegisterModel true "account info"
 // @Success 200 {object} userModel.TokenAccessModel "data"
-// @Failure 400,404 {object} errorResponse
+// @Failure 400,403,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /auth/sign-up [post]
@@ -30,7 +33,13 @@ func (h *Handler) signUp(c *gin.Context) {
 		return
 	}
 
-	data, err := h.services.Authorization.CreateUser(input)
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data, err := h.services.Authorization.CreateUser(input, domain, getSessionData(c))
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -48,12 +57,13 @@ func (h *Handler) signUp(c *gin.Context) {
 
 // @Summary SignIn
 // @Tags auth
-// @Description Авторизация пользователя
+// @Description Авторизация пользователя (при включённой двухфакторной аутентификации возвращается токен ожидания второго фактора)
 // @ID login
 // @Accept  json
 // @Produce  json
 // @Param input body userModel.UserLoginModel true "credentials"
 // @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 200 {object} userModel.MfaRequiredModel "mfa"
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
@@ -68,10 +78,25 @@ func (h *Handler) signIn(c *gin.Context) {
 		return
 	}
 
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	// Вызов метода для авторизации пользователя
-	data, err := h.services.Authorization.LoginUser(input)
+	data, err := h.services.Authorization.LoginUser(input, domain, getSessionData(c))
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		newThrottledErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Для завершения входа требуется второй фактор
+	if data.MfaToken != "" {
+		c.JSON(http.StatusOK, userModel.MfaRequiredModel{
+			MfaRequired: true,
+			MfaToken:    data.MfaToken,
+		})
 		return
 	}
 
@@ -86,29 +111,35 @@ func (h *Handler) signIn(c *gin.Context) {
 	})
 }
 
-// @Summary SignInVK
+// @Summary SignInMfa
 // @Tags auth
-// @Description Авторизация пользователя через VK
-// @ID login_vk
+// @Description Завершение авторизации пользователя вторым фактором (код TOTP или код восстановления)
+// @ID login-mfa
 // @Accept  json
 // @Produce  json
-// @Param input body userModel.UserLoginModel true "credentials"
+// @Param input body userModel.UserMfaLoginModel true "credentials"
 // @Success 200 {object} userModel.TokenAccessModel "data"
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
-// @Router /auth/sign-in/vk [post]
-func (h *Handler) signInVK(c *gin.Context) {
-	var input userModel.UserLoginModel
+// @Router /auth/sign-in/mfa [post]
+func (h *Handler) signInMfa(c *gin.Context) {
+	var input userModel.UserMfaLoginModel
 
 	if err := c.BindJSON(&input); err != nil {
 		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
 		return
 	}
 
-	data, err := h.services.Authorization.LoginUser(input)
+	domain, err := getDomain(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data, err := h.services.Authorization.LoginUserMfa(input, domain, getSessionData(c))
+	if err != nil {
+		newThrottledErrorResponse(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -122,32 +153,197 @@ func (h *Handler) signInVK(c *gin.Context) {
 	})
 }
 
+// @Summary SignInProvider
+// @Tags auth
+// @Description Авторизация пользователя через внешний провайдер (по коду авторизации провайдера)
+// @ID login_provider
+// @Accept  json
+// @Produce  json
+// @Param provider path string true "provider"
+// @Param input body userModel.ProviderCodeModel true "credentials"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/sign-in/oauth2/{provider} [post]
+func (h *Handler) signInProvider(c *gin.Context) {
+	h.signInProviderCode(c, c.Param("provider"))
+}
+
+// @Summary SignInProviderAuthorize
+// @Tags auth
+// @Description Перенаправление на страницу авторизации внешнего провайдера (состояние авторизации сохраняется в cookie)
+// @ID login_provider_authorize
+// @Param provider path string true "provider"
+// @Success 302
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/sign-in/oauth2/{provider}/authorize [get]
+func (h *Handler) signInProviderAuthorize(c *gin.Context) {
+	h.authorizeProvider(c, c.Param("provider"))
+}
+
+// @Summary SignInProviderCallback
+// @Tags auth
+// @Description Авторизация пользователя через внешний провайдер (перенаправление провайдера с кодом авторизации)
+// @ID login_provider_callback
+// @Accept  json
+// @Produce  json
+// @Param provider path string true "provider"
+// @Param code query string true "code"
+// @Param state query string true "state"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/sign-in/oauth2/{provider}/callback [get]
+func (h *Handler) signInProviderCallback(c *gin.Context) {
+	h.signInProviderRedirect(c, c.Param("provider"))
+}
+
+// @Summary SignInVK
+// @Tags auth
+// @Description Авторизация пользователя через VK (по коду авторизации VK)
+// @ID login_vk
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.ProviderCodeModel true "credentials"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/sign-in/vk [post]
+func (h *Handler) signInVK(c *gin.Context) {
+	h.signInProviderCode(c, authConstants.AUTH_TYPE_VK)
+}
+
+// @Summary SignInVKAuthorize
+// @Tags auth
+// @Description Перенаправление на страницу авторизации VK (состояние авторизации сохраняется в cookie)
+// @ID login_vk_authorize
+// @Success 302
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/sign-in/vk/authorize [get]
+func (h *Handler) signInVKAuthorize(c *gin.Context) {
+	h.authorizeProvider(c, authConstants.AUTH_TYPE_VK)
+}
+
+// @Summary SignInVKCallback
+// @Tags auth
+// @Description Авторизация пользователя через VK (перенаправление VK с кодом авторизации)
+// @ID login_vk_callback
+// @Accept  json
+// @Produce  json
+// @Param code query string true "code"
+// @Param state query string true "state"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/sign-in/vk/callback [get]
+func (h *Handler) signInVKCallback(c *gin.Context) {
+	h.signInProviderRedirect(c, authConstants.AUTH_TYPE_VK)
+}
+
 // @Summary SignInOAuth2
 // @Tags auth
 // @Description Авторизация пользователя через Google OAuth2
 // @ID login_oauth2
 // @Accept  json
 // @Produce  json
-// @Param input body userModel.GoogleOAuth2Code true "credentials"
+// @Param input body userModel.ProviderCodeModel true "credentials"
 // @Success 200 {object} userModel.TokenAccessModel "data"
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /auth/sign-in/oauth2 [post]
 func (h *Handler) signInOAuth2(c *gin.Context) {
-	var input userModel.GoogleOAuth2Code
+	h.signInProviderCode(c, authConstants.AUTH_TYPE_GOOGLE)
+}
+
+/* Authorization through provider by code from request body */
+func (h *Handler) signInProviderCode(c *gin.Context, provider string) {
+	var input userModel.ProviderCodeModel
 
 	if err := c.BindJSON(&input); err != nil {
 		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
 		return
 	}
 
-	// For fast tests
-	/*token, _ := configs.AppOAuth2Config.GoogleLogin.Exchange(c, input.Code)
-	_, _ = google_oauth2.RevokeToken(token.AccessToken)
-	return*/
+	h.loginProvider(c, provider, input.Code)
+}
+
+/*
+* Redirect to authorization page of provider
+* (random state is kept in http only cookie and is checked on redirect of provider back)
+ */
+func (h *Handler) authorizeProvider(c *gin.Context, provider string) {
+	authProvider, ok := authService.GetProvider(provider)
+	if !ok {
+		newErrorResponse(c, http.StatusBadRequest, "Данный способ авторизации не поддерживается!")
+		return
+	}
+
+	state, err := authService.GenerateState()
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	address, err := authProvider.AuthCodeURL(c.Request.Context(), state)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Cookie должна передаваться при перенаправлении со страницы провайдера
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(authConstants.OAUTH2_STATE_COOKIE+provider, state,
+		int(authConstants.OAUTH2_STATE_TTL.Seconds()), "/", viper.GetString("environment.domain"), false, true)
+
+	c.Redirect(http.StatusFound, address)
+}
+
+/* Authorization through provider by code from redirect of provider */
+func (h *Handler) signInProviderRedirect(c *gin.Context, provider string) {
+	// Состояние авторизации одноразовое
+	state, err := c.Cookie(authConstants.OAUTH2_STATE_COOKIE + provider)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(authConstants.OAUTH2_STATE_COOKIE+provider, "", -1, "/", viper.GetString("environment.domain"), false, true)
+
+	// Перенаправление должно быть начато этим же браузером (защита от подмены аккаунта при входе)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(c.Query("state"))) != 1 {
+		newErrorResponse(c, http.StatusBadRequest, "invalid state of authorization")
+		return
+	}
+
+	// Пользователь отказался от авторизации через провайдер
+	if errorValue := c.Query("error"); errorValue != "" {
+		newErrorResponse(c, http.StatusBadRequest, c.DefaultQuery("error_description", errorValue))
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		newErrorResponse(c, http.StatusBadRequest, "code is required")
+		return
+	}
+
+	h.loginProvider(c, provider, code)
+}
+
+/* Completion of authorization through provider by authorization code */
+func (h *Handler) loginProvider(c *gin.Context, provider, code string) {
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
 
-	data, err := h.services.Authorization.LoginUserOAuth2(input.Code)
+	data, err := h.services.Authorization.LoginUserProvider(provider, code, domain, getSessionData(c))
 	if err != nil {
 		newErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -183,16 +379,24 @@ func (h *Handler) refresh(c *gin.Context) {
 		return
 	}
 
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	accessToken, _ := c.Get(middlewareConstants.ACCESS_TOKEN_CTX)
 	authTypeValue, _ := c.Get(middlewareConstants.AUTH_TYPE_VALUE_CTX)
 	tokenApi, _ := c.Get(middlewareConstants.TOKEN_API_CTX)
+	sessionUuid, _ := c.Get(middlewareConstants.SESSION_CTX)
 
 	data, err := h.services.Authorization.Refresh(userModel.TokenLogoutDataModel{
 		AccessToken:   accessToken.(string),
 		RefreshToken:  refreshToken,
 		AuthTypeValue: authTypeValue.(string),
 		TokenApi:      tokenApi.(*string),
-	}, refreshToken)
+		SessionUuid:   sessionUuid.(string),
+	}, refreshToken, domain, getSessionData(c))
 
 	if err != nil {
 		newErrorResponse(c, http.StatusUnauthorized, err.Error())
@@ -234,12 +438,15 @@ func (h *Handler) logout(c *gin.Context) {
 	accessToken, _ := c.Get(middlewareConstants.ACCESS_TOKEN_CTX)
 	authTypeValue, _ := c.Get(middlewareConstants.AUTH_TYPE_VALUE_CTX)
 	tokenApi, _ := c.Get(middlewareConstants.TOKEN_API_CTX)
+	sessionUuid, _ := c.Get(middlewareConstants.SESSION_CTX)
 
 	data, err := h.services.Authorization.Logout(userModel.TokenLogoutDataModel{
 		AccessToken:   accessToken.(string),
 		RefreshToken:  refreshToken,
 		AuthTypeValue: authTypeValue.(string),
 		TokenApi:      tokenApi.(*string),
+		SessionUuid:   sessionUuid.(string),
+		TokenId:       c.GetString(middlewareConstants.TOKEN_ID_CTX),
 	})
 
 	if err != nil {
@@ -282,6 +489,61 @@ func (h *Handler) activate(c *gin.Context) {
 	})
 }
 
+// @Summary Resend activation
+// @Tags auth
+// @Description Повторная отправка ссылки для подтверждения аккаунта
+// @ID activate-resend
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.UserEmailModel true "credentials"
+// @Success 200 {object} successResponse "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/activate/resend [post]
+func (h *Handler) resendActivation(c *gin.Context) {
+	var input userModel.UserEmailModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	_, err := h.services.Authorization.ResendActivation(input.Email, getSessionData(c))
+	if err != nil {
+		newThrottledErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse{
+		Message: "Если аккаунт не подтверждён, на Вашу почту была отправлена новая ссылка для подтверждения",
+	})
+}
+
+// @Summary Unlock
+// @Tags auth
+// @Description Разблокировка аккаунта по ссылке из письма (после неудачных попыток входа)
+// @ID unlock
+// @Accept  json
+// @Produce  json
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/unlock [get]
+func (h *Handler) unlock(c *gin.Context) {
+	_, err := h.services.UnlockAccount(c.Params.ByName("link"))
+
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.HTML(http.StatusOK, "account_unlock.html", gin.H{
+		"title": "Разблокировка аккаунта",
+	})
+}
+
 // @Summary Recovery password
 // @Tags auth
 // @Description Запрос на смену пароля пользователем
@@ -302,9 +564,9 @@ func (h *Handler) recoveryPassword(c *gin.Context) {
 		return
 	}
 
-	_, err := h.services.Authorization.RecoveryPassword(input.Email)
+	_, err := h.services.Authorization.RecoveryPassword(input.Email, getSessionData(c))
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newThrottledErrorResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
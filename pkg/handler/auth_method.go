@@ -0,0 +1,141 @@
+package handler
+
+import (
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetAuthMethods
+// @Tags auth-methods
+// @Description Получение списка способов входа пользователя
+// @ID get-auth-methods
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} userModel.AuthMethodsModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/auth-methods/get/all [post]
+func (h *Handler) getAuthMethods(c *gin.Context) {
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.AuthMethod.GetAuthMethods(usersId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary LinkAuthMethod
+// @Tags auth-methods
+// @Description Привязка аккаунта внешнего провайдера к пользователю (по коду авторизации провайдера)
+// @ID link-auth-method
+// @Accept  json
+// @Produce  json
+// @Param provider path string true "provider"
+// @Param input body userModel.ProviderCodeModel true "credentials"
+// @Success 200 {object} userModel.AuthMethodsModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/auth-methods/link/{provider} [post]
+func (h *Handler) linkAuthMethod(c *gin.Context) {
+	var input userModel.ProviderCodeModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.AuthMethod.LinkAuthMethod(usersId, c.Param("provider"), input.Code)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary UnlinkAuthMethod
+// @Tags auth-methods
+// @Description Отвязка способа входа от пользователя (последний способ входа отвязать нельзя)
+// @ID unlink-auth-method
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.AuthMethodValueModel true "credentials"
+// @Success 200 {object} userModel.AuthMethodsModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/auth-methods/unlink [post]
+func (h *Handler) unlinkAuthMethod(c *gin.Context) {
+	var input userModel.AuthMethodValueModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.AuthMethod.UnlinkAuthMethod(usersId, input.Value)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary SetPassword
+// @Tags auth-methods
+// @Description Установка пароля пользователю, зарегистрированному через внешний провайдер (добавляет вход по паролю)
+// @ID set-password
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.SetPasswordModel true "credentials"
+// @Success 200 {object} userModel.AuthMethodsModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/auth-methods/password [post]
+func (h *Handler) setPassword(c *gin.Context) {
+	var input userModel.SetPasswordModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.AuthMethod.SetPassword(usersId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
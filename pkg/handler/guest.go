@@ -1,7 +1,9 @@
 package handler
 
 import (
+	articleModel "main-server/pkg/model/article"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,13 +14,164 @@ import (
 // @ID get-articles
 // @Accept  json
 // @Produce  json
+// @Param input body articleModel.ArticlesFilterModel false "filter"
 // @Success 200 {object} articleModel.ArticlesModel "data"
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /guest/article/get/all [post]
 func (h *Handler) guestGetArticles(c *gin.Context) {
-	data, err := h.services.Guest.GetArticles()
+	var input articleModel.ArticlesFilterModel
+
+	if err := bindArticlesFilter(c, &input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data, err := h.services.Guest.GetArticles(domain.Id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary guestGetArticle
+// @Tags guest
+// @Description Получение опубликованной статьи
+// @ID guest-get-article
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleUuidModel true "credentials"
+// @Success 200 {object} articleModel.ArticleModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /guest/article/get [post]
+func (h *Handler) guestGetArticle(c *gin.Context) {
+	var input articleModel.ArticleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data, err := h.services.Guest.GetArticle(domain.Id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary guestSearchArticles
+// @Tags guest
+// @Description Полнотекстовый поиск по опубликованным статьям
+// @ID guest-search-articles
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticlesSearchRequestModel true "search"
+// @Success 200 {object} articleModel.ArticlesSearchModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /guest/article/search [post]
+func (h *Handler) guestSearchArticles(c *gin.Context) {
+	var input articleModel.ArticlesSearchRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	if strings.TrimSpace(input.Query) == "" {
+		newErrorResponse(c, http.StatusBadRequest, "Пустой поисковый запрос!")
+		return
+	}
+
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data, err := h.services.Guest.SearchArticles(domain.Id, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary guestGetTags
+// @Tags guest
+// @Description Получение облака тегов опубликованных статей
+// @ID guest-get-tags
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} articleModel.TagsModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /guest/tag/get/all [post]
+func (h *Handler) guestGetTags(c *gin.Context) {
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data, err := h.services.Guest.GetTags(domain.Id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary guestGetArticlesByTag
+// @Tags guest
+// @Description Получение списка опубликованных статей по тегу
+// @ID guest-get-articles-by-tag
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticlesByTagRequestModel true "filter"
+// @Success 200 {object} articleModel.ArticlesModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /guest/tag/article/get/all [post]
+func (h *Handler) guestGetArticlesByTag(c *gin.Context) {
+	var input articleModel.ArticlesByTagRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data, err := h.services.Guest.GetArticlesByTag(domain.Id, input)
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
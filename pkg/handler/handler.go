@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"expvar"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
 	_ "main-server/docs"
 
+	actionConstant "main-server/pkg/constant/action"
+	middlewareConstants "main-server/pkg/constant/middleware"
 	route "main-server/pkg/constant/route"
 	service "main-server/pkg/service"
 
@@ -29,6 +34,13 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
 
 	router.MaxMultipartMemory = 50 << 20 // 50 MiB
+
+	// Адрес клиента берётся из X-Forwarded-For только для запросов от доверенных прокси-серверов
+	// (иначе ограничения попыток по IP-адресу обходятся подменой заголовка)
+	if err := router.SetTrustedProxies(viper.GetStringSlice("http.trusted_proxies")); err != nil {
+		logrus.Errorf("invalid trusted proxies, forwarded headers are ignored: %s", err.Error())
+		router.SetTrustedProxies(nil)
+	}
 	router.Static("/public", "./public")
 
 	router.LoadHTMLGlob("pkg/template/*")
@@ -38,19 +50,38 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		//AllowAllOrigins: true,
 		AllowOrigins:     []string{viper.GetString("client_url"), viper.GetString("crm_url")},
 		AllowMethods:     []string{"POST", "GET"},
-		AllowHeaders:     []string{"Origin", "Content-type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-type", "Authorization", middlewareConstants.DOMAIN_HEADER, middlewareConstants.DEVICE_HEADER},
 		AllowCredentials: true,
 	}))
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Открытые ключи подписи токенов доступа (не зависят от домена)
+	wellKnown := router.Group(route.WELL_KNOWN_MAIN_ROUTE)
+	{
+		// URL: /.well-known/jwks.json
+		wellKnown.GET(route.WELL_KNOWN_JWKS_ROUTE, h.getJWKS)
+	}
+
+	// Определение домена для всех последующих запросов
+	router.Use(h.domainIdentity)
+
 	// Запросы
 	auth := router.Group(route.AUTH_MAIN_ROUTE)
 	{
-		auth.POST(route.AUTH_SIGN_UP_ROUTE, h.signUp)
+		auth.POST(route.AUTH_SIGN_UP_ROUTE, h.signUpAllowed, h.signUp)
 		auth.POST(route.AUTH_SIGN_IN_ROUTE, h.signIn)
+		auth.POST(route.AUTH_SIGN_IN_MFA_ROUTE, h.signInMfa)
 		auth.POST(route.AUTH_SIGN_IN_GOOGLE_ROUTE, h.signInOAuth2)
+		auth.POST(route.AUTH_SIGN_IN_VK_ROUTE, h.signInVK)
+		auth.GET(route.AUTH_SIGN_IN_VK_AUTHORIZE_ROUTE, h.signInVKAuthorize)
+		auth.GET(route.AUTH_SIGN_IN_VK_CALLBACK_ROUTE, h.signInVKCallback)
+		auth.POST(route.AUTH_SIGN_IN_PROVIDER_ROUTE, h.signInProvider)
+		auth.GET(route.AUTH_SIGN_IN_PROVIDER_AUTHORIZE_ROUTE, h.signInProviderAuthorize)
+		auth.GET(route.AUTH_SIGN_IN_PROVIDER_CALLBACK_ROUTE, h.signInProviderCallback)
 		auth.GET(route.AUTH_ACTIVATE_ROUTE, h.activate)
+		auth.POST(route.AUTH_ACTIVATE_RESEND_ROUTE, h.resendActivation)
+		auth.GET(route.AUTH_UNLOCK_ROUTE, h.unlock)
 
 		// With middlewares (for get data from access token)
 		auth.POST(route.AUTH_REFRESH_TOKEN_ROUTE, h.userIdentityLogout, h.refresh)
@@ -65,22 +96,109 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	user := router.Group(route.USER_MAIN_ROUTE, h.userIdentity)
 	{
 		// Группа запросов, связанных со статьями
-		article := user.Group(route.USER_ARTICLE_ROUTE, h.userIdentityHasRoleUser)
+		article := user.Group(route.USER_ARTICLE_ROUTE, h.userIdentityHasRoleUser, h.userIdentityActivated(middlewareConstants.ACTIVATION_GROUP_ARTICLE))
 		{
 			// URL: /user/article/create
 			article.POST(route.CREATE_ROUTE, h.createArticle)
 
 			// URL: /user/article/update
-			article.POST(route.UPDATE_ROUTE, h.updateArticle)
+			article.POST(route.UPDATE_ROUTE, h.articleEnforce(actionConstant.MODIFY), h.updateArticle)
 
 			// URL: /user/article/delete
-			article.POST(route.DELETE_ROUTE, h.deleteArticle)
+			article.POST(route.DELETE_ROUTE, h.articleEnforce(actionConstant.DELETE), h.deleteArticle)
 
 			// URL: /user/article/get
-			article.POST(route.GET_ROUTE, h.getArticle)
+			article.POST(route.GET_ROUTE, h.articleEnforce(actionConstant.READ), h.getArticle)
 
 			// URL: /user/article/get/all
 			article.POST(route.GET_ALL_ROUTE, h.getArticles)
+
+			// URL: /user/article/search
+			article.POST(route.SEARCH_ROUTE, h.searchArticles)
+
+			// URL: /user/article/status
+			article.POST(route.USER_STATUS_ROUTE, h.articleEnforce(actionConstant.MODIFY), h.updateArticleStatus)
+
+			// Группа запросов, связанных с корзиной статей
+			trash := article.Group(route.USER_TRASH_ROUTE)
+			{
+				// URL: /user/article/trash/get/all
+				trash.POST(route.GET_ALL_ROUTE, h.getTrashArticles)
+
+				// URL: /user/article/trash/restore
+				trash.POST(route.RESTORE_ROUTE, h.articleEnforce(actionConstant.DELETE), h.restoreArticle)
+			}
+
+			// Группа запросов, связанных с совместным доступом к статьям
+			share := article.Group(route.USER_SHARE_ROUTE)
+			{
+				// URL: /user/article/share/grant
+				share.POST(route.USER_GRANT_ROUTE, h.grantArticleAccess)
+
+				// URL: /user/article/share/revoke
+				share.POST(route.USER_REVOKE_ROUTE, h.revokeArticleAccess)
+
+				// URL: /user/article/share/get/all
+				share.POST(route.GET_ALL_ROUTE, h.getArticleGrants)
+			}
+
+			// Группа запросов, связанных с ревизиями статей
+			revision := article.Group(route.USER_REVISION_ROUTE)
+			{
+				// URL: /user/article/revision/get/all
+				revision.POST(route.GET_ALL_ROUTE, h.articleEnforce(actionConstant.READ), h.getArticleRevisions)
+
+				// URL: /user/article/revision/diff
+				revision.POST(route.DIFF_ROUTE, h.articleEnforce(actionConstant.READ), h.getArticleRevisionsDiff)
+
+				// URL: /user/article/revision/restore
+				revision.POST(route.RESTORE_ROUTE, h.articleEnforce(actionConstant.MODIFY), h.restoreArticleRevision)
+			}
+		}
+
+		// Группа запросов, связанных с сессиями (устройствами) пользователя
+		sessions := user.Group(route.USER_SESSIONS_ROUTE)
+		{
+			// URL: /user/sessions/get/all
+			sessions.POST(route.GET_ALL_ROUTE, h.getSessions)
+
+			// URL: /user/sessions/revoke
+			sessions.POST(route.USER_REVOKE_ROUTE, h.revokeSession)
+
+			// URL: /user/sessions/revoke/others
+			sessions.POST(route.USER_REVOKE_OTHERS_ROUTE, h.revokeOtherSessions)
+		}
+
+		// Группа запросов, связанных с двухфакторной аутентификацией
+		mfa := user.Group(route.USER_MFA_ROUTE)
+		{
+			// URL: /user/mfa/enroll
+			mfa.POST(route.USER_ENROLL_ROUTE, h.enrollMfa)
+
+			// URL: /user/mfa/confirm
+			mfa.POST(route.USER_CONFIRM_ROUTE, h.confirmMfa)
+
+			// URL: /user/mfa/disable
+			mfa.POST(route.USER_DISABLE_ROUTE, h.disableMfa)
+
+			// URL: /user/mfa/recovery-codes
+			mfa.POST(route.USER_RECOVERY_CODES_ROUTE, h.regenerateRecoveryCodes)
+		}
+
+		// Группа запросов, связанных со способами входа пользователя
+		authMethods := user.Group(route.USER_AUTH_METHODS_ROUTE)
+		{
+			// URL: /user/auth-methods/get/all
+			authMethods.POST(route.GET_ALL_ROUTE, h.getAuthMethods)
+
+			// URL: /user/auth-methods/link/:provider
+			authMethods.POST(route.USER_LINK_ROUTE, h.linkAuthMethod)
+
+			// URL: /user/auth-methods/unlink
+			authMethods.POST(route.USER_UNLINK_ROUTE, h.unlinkAuthMethod)
+
+			// URL: /user/auth-methods/password
+			authMethods.POST(route.USER_PASSWORD_ROUTE, h.setPassword)
 		}
 
 		// Группа запросов, связанных с профилем пользователя
@@ -95,7 +213,8 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	}
 
 	// Route group for the moderator
-	moderator := router.Group(route.MODERATOR_MAIN_ROUTE, h.userIdentity, h.userIdentityHasRoleModerator)
+	moderator := router.Group(route.MODERATOR_MAIN_ROUTE, h.userIdentity, h.userIdentityHasRoleModerator,
+		h.userIdentityActivated(middlewareConstants.ACTIVATION_GROUP_MODERATOR))
 	{
 		unchecked := moderator.Group(route.MODERATOR_UNCHECKED_ROUTE)
 		{
@@ -103,8 +222,83 @@ func (h *Handler) InitRoutes() *gin.Engine {
 			{
 				article.POST(route.GET_ROUTE, h.getUncheckedArticle)
 				article.POST(route.GET_ALL_ROUTE, h.getUncheckedArticles)
+
+				// URL: /moderator/unchecked/article/approve
+				article.POST(route.MODERATOR_APPROVE_ROUTE, h.approveArticle)
+
+				// URL: /moderator/unchecked/article/reject
+				article.POST(route.MODERATOR_REJECT_ROUTE, h.rejectArticle)
+
+				// URL: /moderator/unchecked/article/changes
+				article.POST(route.MODERATOR_CHANGES_ROUTE, h.getArticleChanges)
 			}
 		}
+
+		article := moderator.Group(route.MODERATOR_ARTICLE_ROUTE)
+		{
+			// URL: /moderator/article/history
+			article.POST(route.MODERATOR_HISTORY_ROUTE, h.getArticleChecks)
+		}
+	}
+
+	// Группа запросов администратора (домены, роли и назначение ролей)
+	admin := router.Group(route.ADMIN_MAIN_ROUTE, h.userIdentity, h.userIdentityHasRoleAdmin,
+		h.userIdentityActivated(middlewareConstants.ACTIVATION_GROUP_ADMIN))
+	{
+		domain := admin.Group(route.ADMIN_DOMAIN_ROUTE)
+		{
+			// URL: /admin/domain/create
+			domain.POST(route.CREATE_ROUTE, h.createDomain)
+
+			// URL: /admin/domain/update
+			domain.POST(route.UPDATE_ROUTE, h.updateDomain)
+
+			// URL: /admin/domain/delete
+			domain.POST(route.DELETE_ROUTE, h.deleteDomain)
+
+			// URL: /admin/domain/get
+			domain.POST(route.GET_ROUTE, h.getDomain)
+
+			// URL: /admin/domain/get/all
+			domain.POST(route.GET_ALL_ROUTE, h.getDomains)
+		}
+
+		role := admin.Group(route.ADMIN_ROLE_ROUTE)
+		{
+			// URL: /admin/role/create
+			role.POST(route.CREATE_ROUTE, h.createRole)
+
+			// URL: /admin/role/update
+			role.POST(route.UPDATE_ROUTE, h.updateRole)
+
+			// URL: /admin/role/delete
+			role.POST(route.DELETE_ROUTE, h.deleteRole)
+
+			// URL: /admin/role/get
+			role.POST(route.GET_ROUTE, h.getRole)
+
+			// URL: /admin/role/get/all
+			role.POST(route.GET_ALL_ROUTE, h.getRoles)
+
+			// URL: /admin/role/assign
+			role.POST(route.ADMIN_ASSIGN_ROUTE, h.assignRole)
+
+			// URL: /admin/role/remove
+			role.POST(route.ADMIN_REMOVE_ROUTE, h.removeRole)
+
+			// URL: /admin/role/users
+			role.POST(route.ADMIN_USERS_ROUTE, h.getRoleUsers)
+		}
+
+		// Группа запросов, связанных с пользователями
+		users := admin.Group(route.ADMIN_USERS_ROUTE)
+		{
+			// URL: /admin/users/logout
+			users.POST(route.ADMIN_LOGOUT_ROUTE, h.forceLogout)
+		}
+
+		// URL: /admin/metrics (счётчики перезагрузок политики и др.)
+		admin.GET(route.ADMIN_METRICS_ROUTE, gin.WrapH(expvar.Handler()))
 	}
 
 	// Route group for the guest
@@ -112,7 +306,18 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	{
 		article := guest.Group(route.GUEST_ARTICLE_ROUTE)
 		{
+			article.POST(route.GET_ROUTE, h.guestGetArticle)
 			article.POST(route.GET_ALL_ROUTE, h.guestGetArticles)
+			article.POST(route.SEARCH_ROUTE, h.guestSearchArticles)
+		}
+
+		tag := guest.Group(route.GUEST_TAG_ROUTE)
+		{
+			// URL: /guest/tag/get/all
+			tag.POST(route.GET_ALL_ROUTE, h.guestGetTags)
+
+			// URL: /guest/tag/article/get/all
+			tag.POST(route.GUEST_ARTICLE_ROUTE+route.GET_ALL_ROUTE, h.guestGetArticlesByTag)
 		}
 	}
 
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"fmt"
+	authConstants "main-server/pkg/constant/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetJWKS
+// @Tags well-known
+// @Description Получение открытых ключей для проверки подписи токенов доступа (JWKS)
+// @ID get-jwks
+// @Produce  json
+// @Success 200 {object} userModel.JWKSModel "data"
+// @Failure default {object} errorResponse
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) getJWKS(c *gin.Context) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(authConstants.JWKS_CACHE_MAX_AGE.Seconds())))
+	c.JSON(http.StatusOK, h.services.Token.GetJWKS())
+}
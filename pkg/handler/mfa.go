@@ -0,0 +1,140 @@
+package handler
+
+import (
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary EnrollMfa
+// @Tags mfa
+// @Description Подключение двухфакторной аутентификации (секрет, otpauth URI и QR-код в PNG)
+// @ID enroll-mfa
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} userModel.MfaEnrollModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/mfa/enroll [post]
+func (h *Handler) enrollMfa(c *gin.Context) {
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.EnrollMfa(usersId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary ConfirmMfa
+// @Tags mfa
+// @Description Подтверждение подключения двухфакторной аутентификации первым кодом (возвращаются коды восстановления)
+// @ID confirm-mfa
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.MfaCodeModel true "credentials"
+// @Success 200 {object} userModel.MfaRecoveryCodesModel "data"
+// @Failure 400,404,429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/mfa/confirm [post]
+func (h *Handler) confirmMfa(c *gin.Context) {
+	var input userModel.MfaCodeModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.ConfirmMfa(usersId, input, getSessionData(c))
+	if err != nil {
+		newThrottledErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DisableMfa
+// @Tags mfa
+// @Description Отключение двухфакторной аутентификации
+// @ID disable-mfa
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.MfaCodeModel true "credentials"
+// @Success 200 {object} userModel.MfaStatusModel "data"
+// @Failure 400,404,429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/mfa/disable [post]
+func (h *Handler) disableMfa(c *gin.Context) {
+	var input userModel.MfaCodeModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.DisableMfa(usersId, input, getSessionData(c))
+	if err != nil {
+		newThrottledErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary RegenerateRecoveryCodes
+// @Tags mfa
+// @Description Генерация нового набора кодов восстановления
+// @ID regenerate-recovery-codes
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.MfaCodeModel true "credentials"
+// @Success 200 {object} userModel.MfaRecoveryCodesModel "data"
+// @Failure 400,404,429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/mfa/recovery-codes [post]
+func (h *Handler) regenerateRecoveryCodes(c *gin.Context) {
+	var input userModel.MfaCodeModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.RegenerateRecoveryCodes(usersId, input, getSessionData(c))
+	if err != nil {
+		newThrottledErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -1,10 +1,18 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"io"
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
 	middlewareConstants "main-server/pkg/constant/middleware"
 	roleConstant "main-server/pkg/constant/role"
-	authService "main-server/pkg/service/auth"
+	articleModel "main-server/pkg/model/article"
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+	"net"
 	"net/http"
 	"strings"
 
@@ -12,6 +20,52 @@ import (
 	"github.com/spf13/viper"
 )
 
+/*
+* Обработчик для определения домена, в рамках которого выполняется запрос
+* (заголовок X-Domain, затем имя хоста, затем домен серверного приложения по умолчанию)
+ */
+func (h *Handler) domainIdentity(c *gin.Context) {
+	var domain rbacModel.DomainModel
+	var err error
+
+	if value := c.GetHeader(middlewareConstants.DOMAIN_HEADER); value != "" {
+		// Явно указанный домен обязан существовать
+		domain, err = h.services.Domain.GetDomain("value", value)
+	} else {
+		host, _, splitErr := net.SplitHostPort(c.Request.Host)
+		if splitErr != nil {
+			host = c.Request.Host
+		}
+
+		domain, err = h.services.Domain.GetDomain("value", host)
+		if err != nil {
+			domain, err = h.services.Domain.GetDomain("value", viper.GetString("domain"))
+		}
+	}
+
+	if err != nil {
+		newErrorResponse(c, http.StatusNotFound, "Домена не существует!")
+		return
+	}
+
+	c.Set(middlewareConstants.DOMAIN_CTX, domain)
+	c.Set(middlewareConstants.DOMAINS_ID, domain.Id)
+}
+
+/* Обработчик для проверки возможности самостоятельной регистрации в домене */
+func (h *Handler) signUpAllowed(c *gin.Context) {
+	domain, err := getDomain(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !config.IsSignUpDomain(domain.Value) {
+		newErrorResponse(c, http.StatusForbidden, "Регистрация в данном домене недоступна!")
+		return
+	}
+}
+
 /* Обработчик для проверки токена доступа пользователя */
 func (h *Handler) userIdentity(c *gin.Context) {
 	// Получение данных из заголовка Authorization
@@ -29,32 +83,27 @@ func (h *Handler) userIdentity(c *gin.Context) {
 	}
 
 	// Парсинг токена доступа
-	data, err := h.services.Token.ParseToken(headerParts[1], viper.GetString("token.signing_key_access"))
+	data, err := h.services.Token.ParseToken(headerParts[1])
 
 	if err != nil {
 		newErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	// Получение текущего домена серверного приложения
-	domain, err := h.services.Domain.GetDomain("value", viper.GetString("domain"))
+	// Токен доступа действителен только в том домене, для которого он был выдан
+	domain, err := getDomain(c)
 
-	if err != nil {
-		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+	if err != nil || data.DomainUuid != domain.Uuid {
+		newErrorResponse(c, http.StatusUnauthorized, "Токен доступа выдан для другого домена!")
 		return
 	}
 
-	// Проверка токена доступа по принадлежности к определённому типу авторизации пользователя
-	switch data.AuthType.Value {
-	case "GOOGLE":
-		if result, err := authService.VerifyAccessToken(*data.TokenApi); err != nil || result != true {
+	// Доступ, выданный внешним провайдером авторизации, должен оставаться действительным у провайдера
+	if data.AuthType.Value != authConstants.AUTH_TYPE_LOCAL {
+		if result, err := h.services.AuthMethod.VerifyAuthMethod(data.UsersId, data.AuthType.Value); err != nil || !result {
 			newErrorResponse(c, http.StatusUnauthorized, "Не действительный токен доступа")
 			return
 		}
-		break
-
-	case "LOCAL":
-		break
 	}
 
 	// Добавление к контексту дополнительных данных о пользователе
@@ -62,7 +111,8 @@ func (h *Handler) userIdentity(c *gin.Context) {
 	c.Set(middlewareConstants.AUTH_TYPE_VALUE_CTX, data.AuthType.Value)
 	c.Set(middlewareConstants.TOKEN_API_CTX, data.TokenApi)
 	c.Set(middlewareConstants.ACCESS_TOKEN_CTX, headerParts[1])
-	c.Set(middlewareConstants.DOMAINS_ID, domain.Id)
+	c.Set(middlewareConstants.SESSION_CTX, data.SessionUuid)
+	c.Set(middlewareConstants.TOKEN_ID_CTX, data.TokenId)
 }
 
 func (h *Handler) userIdentityLogout(c *gin.Context) {
@@ -79,7 +129,7 @@ func (h *Handler) userIdentityLogout(c *gin.Context) {
 		return
 	}
 
-	data, err := h.services.Token.ParseTokenWithoutValid(headerParts[1], viper.GetString("token.signing_key_access"))
+	data, err := h.services.Token.ParseAccessTokenWithoutValid(headerParts[1])
 
 	if err != nil {
 		newErrorResponse(c, http.StatusUnauthorized, err.Error())
@@ -91,6 +141,8 @@ func (h *Handler) userIdentityLogout(c *gin.Context) {
 	c.Set(middlewareConstants.AUTH_TYPE_VALUE_CTX, data.AuthType.Value)
 	c.Set(middlewareConstants.TOKEN_API_CTX, data.TokenApi)
 	c.Set(middlewareConstants.ACCESS_TOKEN_CTX, headerParts[1])
+	c.Set(middlewareConstants.SESSION_CTX, data.SessionUuid)
+	c.Set(middlewareConstants.TOKEN_ID_CTX, data.TokenId)
 }
 
 func (h *Handler) userIdentityHasRoleUser(c *gin.Context) {
@@ -117,6 +169,121 @@ func (h *Handler) userIdentityHasRoleModerator(c *gin.Context) {
 	}
 }
 
+func (h *Handler) userIdentityHasRoleAdmin(c *gin.Context) {
+	usersId, _ := c.Get(middlewareConstants.USER_CTX)
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
+
+	has, err := h.services.Role.HasRole(usersId.(int), domainsId.(int), roleConstant.ROLE_ADMIN)
+
+	if (err != nil) || (!has) {
+		newErrorResponse(c, http.StatusForbidden, "Нет доступа!")
+		return
+	}
+}
+
+/*
+* Обработчик для проверки подтверждения email-адреса пользователя
+* (проверка выполняется только для групп маршрутов из activation.required_groups, по умолчанию - для статей)
+ */
+func (h *Handler) userIdentityActivated(group string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isActivationRequired(group) {
+			return
+		}
+
+		usersId, _ := c.Get(middlewareConstants.USER_CTX)
+
+		activated, err := h.services.Authorization.IsActivated(usersId.(int))
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if !activated {
+			newErrorResponse(c, http.StatusForbidden, "Необходимо подтвердить email-адрес!")
+			return
+		}
+	}
+}
+
+func isActivationRequired(group string) bool {
+	if !viper.IsSet("activation.required_groups") {
+		return group == middlewareConstants.ACTIVATION_GROUP_ARTICLE
+	}
+
+	for _, item := range viper.GetStringSlice("activation.required_groups") {
+		if item == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+* Обработчик для проверки прав пользователя на действие со статьёй
+* (UUID статьи берётся из поля uuid тела запроса, тело запроса сохраняется для обработчика)
+ */
+func (h *Handler) articleEnforce(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Тело запроса читается в память целиком, поэтому его размер ограничен
+		if c.Request.ContentLength > middlewareConstants.ENFORCE_BODY_LIMIT {
+			newErrorResponse(c, http.StatusRequestEntityTooLarge, "request body is too large")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, middlewareConstants.ENFORCE_BODY_LIMIT))
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+		var input articleModel.ArticleUuidModel
+
+		if err := json.Unmarshal(body, &input); err != nil || input.Uuid == "" {
+			newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+			return
+		}
+
+		usersId, _ := c.Get(middlewareConstants.USER_CTX)
+		domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
+
+		has, err := h.services.Policy.Enforce(usersId.(int), domainsId.(int), input.Uuid, action)
+
+		if (err != nil) || (!has) {
+			newErrorResponse(c, http.StatusForbidden, "Нет доступа!")
+			return
+		}
+
+		c.Set(middlewareConstants.OBJECT_CTX, input.Uuid)
+	}
+}
+
+func getDomain(c *gin.Context) (rbacModel.DomainModel, error) {
+	domain, ok := c.Get(middlewareConstants.DOMAIN_CTX)
+	if !ok {
+		return rbacModel.DomainModel{}, errors.New("domain not found")
+	}
+
+	domainModel, ok := domain.(rbacModel.DomainModel)
+	if !ok {
+		return rbacModel.DomainModel{}, errors.New("domain is of invalid type")
+	}
+
+	return domainModel, nil
+}
+
+/* Сведения об устройстве, с которого выполняется запрос */
+func getSessionData(c *gin.Context) userModel.SessionDataModel {
+	return userModel.SessionDataModel{
+		Device:    c.GetHeader(middlewareConstants.DEVICE_HEADER),
+		UserAgent: c.Request.UserAgent(),
+		Ip:        c.ClientIP(),
+	}
+}
+
 func getUserId(c *gin.Context) (int, error) {
 	id, ok := c.Get(middlewareConstants.USER_CTX)
 	if !ok {
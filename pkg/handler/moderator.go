@@ -42,13 +42,137 @@ func (h *Handler) getUncheckedArticle(c *gin.Context) {
 // @ID get-articles
 // @Accept  json
 // @Produce  json
+// @Param input body articleModel.ArticlesFilterModel false "filter"
 // @Success 200 {object} articleModel.ArticlesModel "data"
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /moderator/unchecked/article/get/all [post]
 func (h *Handler) getUncheckedArticles(c *gin.Context) {
-	data, err := h.services.Moderator.GetUncheckedArticles(c)
+	var input articleModel.ArticlesFilterModel
+
+	if err := bindArticlesFilter(c, &input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Moderator.GetUncheckedArticles(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary ApproveArticle
+// @Tags moderator
+// @Description Одобрение статьи модератором
+// @ID approve-article
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleCheckRequestModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /moderator/unchecked/article/approve [post]
+func (h *Handler) approveArticle(c *gin.Context) {
+	var input articleModel.ArticleCheckRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Moderator.ApproveArticle(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary RejectArticle
+// @Tags moderator
+// @Description Отклонение статьи модератором с указанием причины
+// @ID reject-article
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleCheckRequestModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /moderator/unchecked/article/reject [post]
+func (h *Handler) rejectArticle(c *gin.Context) {
+	var input articleModel.ArticleCheckRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Moderator.RejectArticle(c, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetArticleChecks
+// @Tags moderator
+// @Description Получение истории решений модераторов по статье
+// @ID get-article-checks
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleUuidModel true "credentials"
+// @Success 200 {object} articleModel.ArticleCheckedHistoryModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /moderator/article/history [post]
+func (h *Handler) getArticleChecks(c *gin.Context) {
+	var input articleModel.ArticleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Moderator.GetArticleChecks(input, c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetArticleChanges
+// @Tags moderator
+// @Description Получение изменений статьи с момента последнего одобрения
+// @ID get-article-changes
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleUuidModel true "credentials"
+// @Success 200 {object} articleModel.ArticleRevisionDiffModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /moderator/unchecked/article/changes [post]
+func (h *Handler) getArticleChanges(c *gin.Context) {
+	var input articleModel.ArticleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Moderator.GetArticleChanges(input, c)
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
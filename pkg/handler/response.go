@@ -1,6 +1,11 @@
 package handler
 
 import (
+	"errors"
+	authService "main-server/pkg/service/auth"
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -26,3 +31,15 @@ func NewErrorResponse(c *gin.Context, statusCode int, message string) {
 	logrus.Error(message)
 	c.AbortWithStatusJSON(statusCode, errorResponse{message})
 }
+
+/* Error response, which reports delay before next attempt for throttled attempts of authentication */
+func newThrottledErrorResponse(c *gin.Context, statusCode int, err error) {
+	var throttleErr *authService.ThrottleError
+
+	if errors.As(err, &throttleErr) {
+		c.Header("Retry-After", strconv.Itoa(throttleErr.RetryAfterSeconds()))
+		statusCode = http.StatusTooManyRequests
+	}
+
+	newErrorResponse(c, statusCode, err.Error())
+}
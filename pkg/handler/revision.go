@@ -0,0 +1,95 @@
+package handler
+
+import (
+	articleModel "main-server/pkg/model/article"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetArticleRevisions
+// @Tags revision
+// @Description Получение списка ревизий статьи
+// @ID get-article-revisions
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleUuidModel true "credentials"
+// @Success 200 {object} articleModel.ArticleRevisionsModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/revision/get/all [post]
+func (h *Handler) getArticleRevisions(c *gin.Context) {
+	var input articleModel.ArticleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.GetArticleRevisions(input, c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetArticleRevisionsDiff
+// @Tags revision
+// @Description Сравнение двух ревизий статьи
+// @ID get-article-revisions-diff
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleRevisionDiffRequestModel true "credentials"
+// @Success 200 {object} articleModel.ArticleRevisionDiffModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/revision/diff [post]
+func (h *Handler) getArticleRevisionsDiff(c *gin.Context) {
+	var input articleModel.ArticleRevisionDiffRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.GetArticleRevisionsDiff(input, c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary RestoreArticleRevision
+// @Tags revision
+// @Description Восстановление статьи из ревизии
+// @ID restore-article-revision
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleRevisionRestoreRequestModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/revision/restore [post]
+func (h *Handler) restoreArticleRevision(c *gin.Context) {
+	var input articleModel.ArticleRevisionRestoreRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.RestoreArticleRevision(input, c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
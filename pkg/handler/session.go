@@ -0,0 +1,98 @@
+package handler
+
+import (
+	middlewareConstants "main-server/pkg/constant/middleware"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetSessions
+// @Tags sessions
+// @Description Получение списка сессий (устройств) пользователя
+// @ID get-sessions
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} userModel.SessionsModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/sessions/get/all [post]
+func (h *Handler) getSessions(c *gin.Context) {
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.Session.GetSessions(usersId, c.GetString(middlewareConstants.SESSION_CTX))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary RevokeSession
+// @Tags sessions
+// @Description Завершение одной сессии пользователя
+// @ID revoke-session
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.SessionUuidModel true "credentials"
+// @Success 200 {object} userModel.SessionRevokeModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/sessions/revoke [post]
+func (h *Handler) revokeSession(c *gin.Context) {
+	var input userModel.SessionUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.Session.RevokeSession(usersId, input.Uuid)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary RevokeOtherSessions
+// @Tags sessions
+// @Description Завершение всех сессий пользователя, кроме текущей
+// @ID revoke-other-sessions
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} userModel.SessionRevokeModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/sessions/revoke/others [post]
+func (h *Handler) revokeOtherSessions(c *gin.Context) {
+	usersId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	data, err := h.services.Session.RevokeOtherSessions(usersId, c.GetString(middlewareConstants.SESSION_CTX))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
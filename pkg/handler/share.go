@@ -0,0 +1,95 @@
+package handler
+
+import (
+	articleModel "main-server/pkg/model/article"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GrantArticleAccess
+// @Tags share
+// @Description Предоставление пользователю доступа к статье (read или modify)
+// @ID grant-article-access
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleGrantRequestModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/share/grant [post]
+func (h *Handler) grantArticleAccess(c *gin.Context) {
+	var input articleModel.ArticleGrantRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.GrantArticleAccess(input, c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary RevokeArticleAccess
+// @Tags share
+// @Description Отзыв доступа пользователя к статье
+// @ID revoke-article-access
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleRevokeRequestModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/share/revoke [post]
+func (h *Handler) revokeArticleAccess(c *gin.Context) {
+	var input articleModel.ArticleRevokeRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.RevokeArticleAccess(input, c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetArticleGrants
+// @Tags share
+// @Description Получение списка пользователей, имеющих доступ к статье
+// @ID get-article-grants
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleUuidModel true "credentials"
+// @Success 200 {object} articleModel.ArticleGrantsModel "data"
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/article/share/get/all [post]
+func (h *Handler) getArticleGrants(c *gin.Context) {
+	var input articleModel.ArticleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.User.GetArticleGrants(input, c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,15 @@
+package metric
+
+import "expvar"
+
+/* Counters of policy reloads (published by expvar) */
+var (
+	// Number of notifications about changes of rules table
+	PolicyNotifications = expvar.NewInt("casbin_policy_notifications")
+
+	// Number of reloads of policy from database
+	PolicyReloads = expvar.NewInt("casbin_policy_reloads")
+
+	// Number of failed reloads of policy
+	PolicyReloadErrors = expvar.NewInt("casbin_policy_reload_errors")
+)
@@ -10,6 +10,10 @@ type ArticleCreateRequestModel struct {
 	Filepath *string                 `json:"filepath" binding:"required"`
 	Tags     string                  `json:"tags" binding:"required"`
 	Files    *[]ArticlesFilesDBModel `json:"files" binding:"required"`
+
+	// Статус новой статьи (draft или review, по умолчанию review) и время её публикации
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 /* Model data for request update article */
@@ -31,6 +35,13 @@ type FileArticleExModel struct {
 	Id       int
 }
 
+/* Model data for request change status of article */
+type ArticleStatusRequestModel struct {
+	Uuid      string     `json:"uuid" binding:"required"`
+	Status    string     `json:"status" binding:"required"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
 type ArticleSuccessModel struct {
 	Success bool `json:"success"`
 }
@@ -42,12 +53,32 @@ type ArticleModel struct {
 	Text      string                 `json:"text" binding:"required"`
 	Tags      string                 `json:"tags" binding:"required"`
 	Files     []ArticlesFilesDBModel `json:"files" binding:"required"`
+	Status    string                 `json:"status" binding:"required"`
+	PublishAt *time.Time             `json:"publish_at"`
 	CreatedAt time.Time              `json:"created_at" binding:"required"`
 	UpdatedAt time.Time              `json:"updated_at" binding:"required"`
+	DeletedAt *time.Time             `json:"deleted_at,omitempty"`
+	LastCheck *ArticleCheckedModel   `json:"last_check,omitempty"`
+	Author    *string                `json:"author,omitempty"`
+
+	// Уровень доступа к чужой статье, которой поделился её автор (read или modify)
+	Access string `json:"access,omitempty"`
 }
 
 type ArticlesModel struct {
-	Articles []ArticleModel `json:"articles" binding:"required"`
+	Articles   []ArticleModel `json:"articles" binding:"required"`
+	NextCursor *string        `json:"next_cursor"`
+	Total      int            `json:"total"`
+}
+
+/* Model of parameters for getting a page of articles */
+type ArticlesFilterModel struct {
+	Limit    int        `json:"limit"`
+	Cursor   *string    `json:"cursor"`
+	Order    string     `json:"order"`
+	DateFrom *time.Time `json:"date_from"`
+	DateTo   *time.Time `json:"date_to"`
+	Tags     []string   `json:"tags"`
 }
 
 type ArticleUuidModel struct {
@@ -55,16 +86,26 @@ type ArticleUuidModel struct {
 }
 
 type ArticleDBModel struct {
-	Id        int       `json:"id" binding:"required" db:"id"`
-	Uuid      string    `json:"uuid" binding:"required" db:"uuid"`
-	UsersId   int       `json:"users_id" binding:"required" db:"users_id"`
-	Filepath  string    `json:"filepath" binding:"required" db:"filepath"`
-	Filename  string    `json:"filename" binding:"required" db:"filename"`
-	Title     string    `json:"title" binding:"required" db:"title"`
-	Text      string    `json:"text" binding:"required" db:"text"`
-	Tags      string    `json:"tags" binding:"required" db:"tags"`
-	CreatedAt time.Time `json:"created_at" binding:"required" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" binding:"required" db:"updated_at"`
+	Id        int        `json:"id" binding:"required" db:"id"`
+	Uuid      string     `json:"uuid" binding:"required" db:"uuid"`
+	UsersId   int        `json:"users_id" binding:"required" db:"users_id"`
+	DomainsId int        `json:"domains_id" db:"domains_id"`
+	Filepath  string     `json:"filepath" binding:"required" db:"filepath"`
+	Filename  string     `json:"filename" binding:"required" db:"filename"`
+	Title     string     `json:"title" binding:"required" db:"title"`
+	Text      string     `json:"text" binding:"required" db:"text"`
+	Tags      string     `json:"tags" binding:"required" db:"tags"`
+	CreatedAt time.Time  `json:"created_at" binding:"required" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" binding:"required" db:"updated_at"`
+	Status    string     `json:"status" binding:"required" db:"status"`
+	PublishAt *time.Time `json:"publish_at" db:"publish_at"`
+	DeletedAt *time.Time `json:"deleted_at" db:"deleted_at"`
+}
+
+/* Article data with public nickname of author */
+type ArticleAuthorDBModel struct {
+	ArticleDBModel
+	Author *string `json:"author" db:"author"`
 }
 
 type ArticlesFilesDBModel struct {
@@ -74,6 +115,12 @@ type ArticlesFilesDBModel struct {
 	Filepath string `json:"filepath" binding:"required" db:"filepath"`
 }
 
+/* File of article together with ID of this article (for batch loading) */
+type ArticleFileDBModel struct {
+	ArticlesId int `json:"articles_id" db:"articles_id"`
+	ArticlesFilesDBModel
+}
+
 type ArticlesFilesModel struct {
 	FilesId    int `json:"files_id" db:"files_id"`
 	Index      int `json:"index" binding:"required" db:"index"`
@@ -98,3 +145,28 @@ type ArticleDataModel struct {
 type ArticlesFilesIndexModel struct {
 	Index int `json:"index" binding:"required" db:"index"`
 }
+
+/* Model data for request full-text search of articles */
+type ArticlesSearchRequestModel struct {
+	Query  string `json:"query" binding:"required"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+/* Found article with its rank and highlighted snippet */
+type ArticleSearchDBModel struct {
+	ArticleAuthorDBModel
+	Rank    float64 `json:"rank" db:"rank"`
+	Snippet string  `json:"snippet" db:"snippet"`
+}
+
+type ArticleSearchModel struct {
+	ArticleModel
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+type ArticlesSearchModel struct {
+	Articles []ArticleSearchModel `json:"articles" binding:"required"`
+	Total    int                  `json:"total"`
+}
@@ -0,0 +1,23 @@
+package article
+
+import "time"
+
+/* Model data for request approve or reject article */
+type ArticleCheckRequestModel struct {
+	Uuid   string `json:"uuid" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+/* Model of a moderator decision for response */
+type ArticleCheckedModel struct {
+	ModeratorUuid *string   `json:"moderator_uuid" db:"moderator_uuid"`
+	IsApproved    bool      `json:"is_approved" db:"is_approved"`
+	Reason        string    `json:"reason" db:"reason"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+/* Model of decision history for article */
+type ArticleCheckedHistoryModel struct {
+	Uuid   string                `json:"uuid" binding:"required"`
+	Checks []ArticleCheckedModel `json:"checks" binding:"required"`
+}
@@ -0,0 +1,73 @@
+package article
+
+import (
+	util "main-server/pkg/util"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+/* Model of article revision from the articles_revisions table */
+type ArticleRevisionDBModel struct {
+	Id         int            `json:"id" db:"id"`
+	ArticlesId int            `json:"articles_id" db:"articles_id"`
+	Version    int            `json:"version" db:"version"`
+	UsersId    *int           `json:"users_id" db:"users_id"`
+	Title      string         `json:"title" db:"title"`
+	Text       string         `json:"text" db:"text"`
+	Tags       string         `json:"tags" db:"tags"`
+	Filename   string         `json:"filename" db:"filename"`
+	Filepath   string         `json:"filepath" db:"filepath"`
+	Files      types.JSONText `json:"files" db:"files"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
+
+/* Model of article revision for response */
+type ArticleRevisionModel struct {
+	Version   int                    `json:"version"`
+	Title     string                 `json:"title"`
+	Text      string                 `json:"text"`
+	Tags      string                 `json:"tags"`
+	Filename  string                 `json:"-"`
+	Filepath  string                 `json:"filepath"`
+	Files     []ArticlesFilesDBModel `json:"files"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+/* Short information about article revision */
+type ArticleRevisionShortModel struct {
+	Version   int       `json:"version" db:"version"`
+	Title     string    `json:"title" db:"title"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type ArticleRevisionsModel struct {
+	Uuid      string                      `json:"uuid" binding:"required"`
+	Revisions []ArticleRevisionShortModel `json:"revisions" binding:"required"`
+}
+
+/* Model data for request diff between two revisions (version 0 of "from" is an empty article) */
+type ArticleRevisionDiffRequestModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+	From int    `json:"from" binding:"min=0"`
+	To   int    `json:"to" binding:"required"`
+}
+
+/* Model data for request restore revision */
+type ArticleRevisionRestoreRequestModel struct {
+	Uuid    string `json:"uuid" binding:"required"`
+	Version int    `json:"version" binding:"required"`
+}
+
+/* Model of changes between two revisions of article */
+type ArticleRevisionDiffModel struct {
+	Uuid              string          `json:"uuid"`
+	From              int             `json:"from"`
+	To                int             `json:"to"`
+	Title             []util.DiffLine `json:"title"`
+	Text              []util.DiffLine `json:"text"`
+	Tags              []util.DiffLine `json:"tags"`
+	TitleImageChanged bool            `json:"title_image_changed"`
+	FilesAdded        []int           `json:"files_added"`
+	FilesRemoved      []int           `json:"files_removed"`
+}
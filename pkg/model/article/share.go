@@ -0,0 +1,34 @@
+package article
+
+/* Model data for request grant access to article */
+type ArticleGrantRequestModel struct {
+	Uuid   string `json:"uuid" binding:"required"`
+	Email  string `json:"email" binding:"required"`
+	Access string `json:"access" binding:"required"`
+}
+
+/* Model data for request revoke access to article */
+type ArticleRevokeRequestModel struct {
+	Uuid  string `json:"uuid" binding:"required"`
+	Email string `json:"email" binding:"required"`
+}
+
+/* User who has access to article (co-author) */
+type ArticleGrantDBModel struct {
+	Id       int     `json:"-" db:"id"`
+	Uuid     string  `json:"uuid" db:"uuid"`
+	Email    string  `json:"email" db:"email"`
+	Nickname *string `json:"nickname" db:"nickname"`
+}
+
+type ArticleGrantModel struct {
+	UserUuid string  `json:"user_uuid"`
+	Email    string  `json:"email"`
+	Nickname *string `json:"nickname"`
+	Access   string  `json:"access"`
+}
+
+type ArticleGrantsModel struct {
+	Uuid   string              `json:"uuid"`
+	Grants []ArticleGrantModel `json:"grants"`
+}
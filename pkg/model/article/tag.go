@@ -0,0 +1,18 @@
+package article
+
+/* Model of tag with number of articles using it */
+type TagCountModel struct {
+	Value string `json:"value" db:"value"`
+	Count int    `json:"count" db:"count"`
+}
+
+/* Model of tag cloud */
+type TagsModel struct {
+	Tags []TagCountModel `json:"tags" binding:"required"`
+}
+
+/* Model data for request articles by tag */
+type ArticlesByTagRequestModel struct {
+	Tag string `json:"tag" binding:"required"`
+	ArticlesFilterModel
+}
@@ -0,0 +1,77 @@
+package rbac
+
+/* Model data for request create domain */
+type DomainCreateRequestModel struct {
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+/* Model data for request update domain */
+type DomainUpdateRequestModel struct {
+	Uuid        string `json:"uuid" binding:"required"`
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+type DomainUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+type DomainsModel struct {
+	Domains []DomainModel `json:"domains"`
+}
+
+/* Model data for request create role in domain */
+type RoleCreateRequestModel struct {
+	DomainUuid  string `json:"domain_uuid" binding:"required"`
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+/* Model data for request update role */
+type RoleUpdateRequestModel struct {
+	Uuid        string `json:"uuid" binding:"required"`
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+type RoleUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+/* Model data for request get roles (all roles or roles of domain) */
+type RolesRequestModel struct {
+	DomainUuid *string `json:"domain_uuid"`
+}
+
+type RolesModel struct {
+	Roles []RoleModel `json:"roles"`
+}
+
+/* Model data for request assign (remove) role to user */
+type RoleAssignRequestModel struct {
+	UserUuid string `json:"user_uuid" binding:"required"`
+	RoleUuid string `json:"role_uuid" binding:"required"`
+}
+
+/* Model data for request of user (force logout) */
+type UserUuidModel struct {
+	UserUuid string `json:"user_uuid" binding:"required"`
+}
+
+/* User who has role in domain */
+type RoleUserModel struct {
+	Id       int     `json:"-" db:"id"`
+	Uuid     string  `json:"uuid" db:"uuid"`
+	Email    string  `json:"email" db:"email"`
+	Nickname *string `json:"nickname" db:"nickname"`
+}
+
+type RoleUsersModel struct {
+	Role  RoleModel       `json:"role"`
+	Users []RoleUserModel `json:"users"`
+}
+
+type RbacSuccessModel struct {
+	Success bool `json:"success"`
+}
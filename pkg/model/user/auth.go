@@ -1,10 +1,25 @@
 package user
 
-type GoogleOAuth2Code struct {
-	Code string `json:"code" binding:"required"`
-}
-
 type ResetPasswordModel struct {
 	Token    string `json:"token" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
+
+/* Model of user profile from VK API (method users.get) */
+type VKUserModel struct {
+	Id        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+/* Model of error from VK API */
+type VKErrorModel struct {
+	ErrorCode int    `json:"error_code"`
+	ErrorMsg  string `json:"error_msg"`
+}
+
+/* Model of response of VK API method users.get */
+type VKUsersResponseModel struct {
+	Response []VKUserModel `json:"response"`
+	Error    *VKErrorModel `json:"error"`
+}
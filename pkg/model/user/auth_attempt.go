@@ -0,0 +1,15 @@
+package user
+
+import "time"
+
+/* Model of failed attempts of authentication by account (email) or by IP address */
+type AuthAttemptModel struct {
+	Id              int        `json:"id" db:"id"`
+	Scope           string     `json:"scope" db:"scope"`
+	Kind            string     `json:"kind" db:"kind"`
+	Value           string     `json:"value" db:"value"`
+	Failures        int        `json:"failures" db:"failures"`
+	LastFailureAt   time.Time  `json:"last_failure_at" db:"last_failure_at"`
+	LockedUntil     *time.Time `json:"locked_until" db:"locked_until"`
+	UnlockTokenHash *string    `json:"-" db:"unlock_token_hash"`
+}
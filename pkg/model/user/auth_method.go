@@ -0,0 +1,23 @@
+package user
+
+import "time"
+
+/* Login method linked to the user (local password or external provider) */
+type AuthMethodModel struct {
+	Value     string    `json:"value" db:"value"`
+	Email     *string   `json:"email" db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type AuthMethodsModel struct {
+	AuthMethods []AuthMethodModel `json:"auth_methods"`
+}
+
+type AuthMethodValueModel struct {
+	Value string `json:"value" binding:"required"`
+}
+
+/* Model for setting password to the user without local login method */
+type SetPasswordModel struct {
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,47 @@
+package user
+
+import "time"
+
+type UserTotpModel struct {
+	Id          int        `json:"id" db:"id"`
+	UsersId     int        `json:"users_id" db:"users_id"`
+	Secret      string     `json:"secret" db:"secret"`
+	IsEnabled   bool       `json:"is_enabled" db:"is_enabled"`
+	LastStep    int64      `json:"last_step" db:"last_step"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at" db:"confirmed_at"`
+}
+
+type MfaEnrollModel struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+	QrCode string `json:"qr_code"`
+}
+
+type MfaCodeModel struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MfaRecoveryCodesModel struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MfaStatusModel struct {
+	IsEnabled bool `json:"is_enabled"`
+}
+
+/* Second step of sign-in (code from authenticator app or recovery code) */
+type UserMfaLoginModel struct {
+	MfaToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type MfaRequiredModel struct {
+	MfaRequired bool   `json:"mfa_required"`
+	MfaToken    string `json:"mfa_token"`
+}
+
+type MfaTokenOutputParse struct {
+	UsersId    int    `json:"users_id"`
+	DomainUuid string `json:"domain_uuid"`
+}
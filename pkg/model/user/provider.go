@@ -0,0 +1,32 @@
+package user
+
+import "time"
+
+/* Model of user profile received from external authentication provider */
+type ProviderUserModel struct {
+	Subject       string `json:"subject"`        // Идентификатор пользователя у провайдера
+	Email         string `json:"email"`          // Email-адрес пользователя
+	EmailVerified bool   `json:"email_verified"` // Подтверждён ли email-адрес провайдером
+	GivenName     string `json:"given_name"`     // Имя
+	FamilyName    string `json:"family_name"`    // Фамилия
+	Name          string `json:"name"`           // Полное имя
+}
+
+/* Model of authorization code of external provider */
+type ProviderCodeModel struct {
+	Code string `json:"code" binding:"required"`
+}
+
+/* Model of account of external provider linked to the user (tokens are encrypted) */
+type ExternalIdentityModel struct {
+	Id           int        `json:"id" db:"id"`
+	UsersId      int        `json:"users_id" db:"users_id"`
+	AuthTypesId  int        `json:"auth_types_id" db:"auth_types_id"`
+	Subject      string     `json:"subject" db:"subject"`
+	Email        *string    `json:"email" db:"email"`
+	AccessToken  *string    `json:"-" db:"access_token"`
+	RefreshToken *string    `json:"-" db:"refresh_token"`
+	ExpiresAt    *time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package user
+
+import "time"
+
+/* Information about the device, from which user signs in */
+type SessionDataModel struct {
+	Device    string `json:"device"`
+	UserAgent string `json:"user_agent"`
+	Ip        string `json:"ip"`
+}
+
+type SessionModel struct {
+	Uuid       string    `json:"uuid" db:"uuid"`
+	Device     *string   `json:"device" db:"device"`
+	UserAgent  *string   `json:"user_agent" db:"user_agent"`
+	Ip         *string   `json:"ip" db:"ip"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at" db:"last_seen_at"`
+	IsCurrent  bool      `json:"is_current" db:"-"`
+}
+
+type SessionsModel struct {
+	Sessions []SessionModel `json:"sessions"`
+}
+
+type SessionUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+type SessionRevokeModel struct {
+	Count int64 `json:"count"`
+}
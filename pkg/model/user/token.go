@@ -1,10 +1,19 @@
 package user
 
+import "time"
+
 type TokenModel struct {
-	Id           int    `json:"id" db:"id"`
-	UsersId      int    `json:"users_id" db:"users_id"`
-	AccessToken  string `json:"access_token" db:"access_token"`
-	RefreshToken string `json:"refresh_token" db:"refresh_token"`
+	Id           int       `json:"id" db:"id"`
+	UsersId      int       `json:"users_id" db:"users_id"`
+	AccessToken  string    `json:"access_token" db:"access_token"`
+	RefreshToken string    `json:"refresh_token" db:"refresh_token"`
+	Uuid         string    `json:"uuid" db:"uuid"`
+	Device       *string   `json:"device" db:"device"`
+	UserAgent    *string   `json:"user_agent" db:"user_agent"`
+	Ip           *string   `json:"ip" db:"ip"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	LastSeenAt   time.Time `json:"last_seen_at" db:"last_seen_at"`
+	AuthTypesId  *int      `json:"auth_types_id" db:"auth_types_id"`
 }
 
 type TokenDataModel struct {
@@ -17,12 +26,17 @@ type TokenLogoutDataModel struct {
 	TokenApi      *string `json:"token_api"`
 	RefreshToken  string  `json:"refresh_token"`
 	AuthTypeValue string  `json:"auth_type_value"`
+	SessionUuid   string  `json:"session_uuid"`
+	TokenId       string  `json:"token_id"`
 }
 
 type TokenOutputParse struct {
-	UsersId  int           `json:"users_id"`
-	AuthType AuthTypeModel `json:"auth_types"`
-	TokenApi *string       `json:"token_api"`
+	UsersId     int           `json:"users_id"`
+	AuthType    AuthTypeModel `json:"auth_types"`
+	TokenApi    *string       `json:"token_api"`
+	DomainUuid  string        `json:"domain_uuid"`
+	SessionUuid string        `json:"session_uuid"`
+	TokenId     string        `json:"token_id"`
 }
 
 type TokenOutputParseString struct {
@@ -48,3 +62,20 @@ type ResetTokenModel struct {
 	UsersId int    `json:"users_id" db:"users_id"`
 	Token   string `json:"token" db:"token"`
 }
+
+/* Public key of access tokens signing (RFC 7517 JSON Web Key) */
+type JWKModel struct {
+	Kty string `json:"kty"`           // Тип ключа: RSA или OKP (Ed25519)
+	Use string `json:"use"`           // Назначение ключа (подпись)
+	Alg string `json:"alg"`           // Алгоритм подписи: RS256 или EdDSA
+	Kid string `json:"kid"`           // Идентификатор ключа
+	N   string `json:"n,omitempty"`   // Модуль RSA
+	E   string `json:"e,omitempty"`   // Экспонента RSA
+	Crv string `json:"crv,omitempty"` // Кривая OKP
+	X   string `json:"x,omitempty"`   // Открытый ключ OKP
+}
+
+/* Set of public keys of access tokens signing (JWKS) */
+type JWKSModel struct {
+	Keys []JWKModel `json:"keys"`
+}
@@ -1,11 +1,14 @@
 package user
 
+import "time"
+
 /* A model for working with an instance of user data from the users table */
 type UserModel struct {
-	Id       int    `json:"id" db:"id"`
-	Uuid     string `json:"uuid" binding:"required" db:"uuid"`
-	Email    string `json:"email" binding:"required" db:"email"`
-	Password string `json:"password" binding:"required" db:"password"`
+	Id           int     `json:"id" db:"id"`
+	Uuid         string  `json:"uuid" binding:"required" db:"uuid"`
+	Email        string  `json:"email" binding:"required" db:"email"`
+	Password     *string `json:"password" db:"password"` // Отсутствует у пользователей без локального способа входа
+	TokenVersion int     `json:"-" db:"token_version"`   // Версия токенов доступа (увеличение отзывает выданные токены)
 }
 
 /* A model for working with data during user registration (JSON parsing, etc.) */
@@ -50,12 +53,14 @@ type UserLoginOAuth2Model struct {
 type UserAuthDataModel struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	MfaToken     string `json:"mfa_token"`
 }
 
 /* A model representing the user's activation data */
 type UserActivateModel struct {
-	ActivationLink string `json:"activation_link" db:"activation_link"`
-	IsActivated    bool   `json:"is_activated" db:"is_activated"`
+	ActivationLink string     `json:"activation_link" db:"activation_link"`
+	IsActivated    bool       `json:"is_activated" db:"is_activated"`
+	ExpiresAt      *time.Time `json:"expires_at" db:"expires_at"` // Срок действия ссылки (отсутствует у ссылок, выданных до его введения)
 }
 
 /* A model for representing authorization types */
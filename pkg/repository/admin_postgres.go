@@ -0,0 +1,574 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	middlewareConstants "main-server/pkg/constant/middleware"
+	roleConstant "main-server/pkg/constant/role"
+	tableConstants "main-server/pkg/constant/table"
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+	"strconv"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+)
+
+/* Structure for this repository */
+type AdminPostgres struct {
+	db       *sqlx.DB
+	enforcer *casbin.SyncedEnforcer
+	domain   *DomainPostgres
+}
+
+/* Function for create repository */
+func NewAdminPostgres(db *sqlx.DB, enforcer *casbin.SyncedEnforcer, domain *DomainPostgres) *AdminPostgres {
+	return &AdminPostgres{
+		db:       db,
+		enforcer: enforcer,
+		domain:   domain,
+	}
+}
+
+/*
+* Scope of administrator: administrator manages only the domain of request,
+* administrator of domain of server application (super administrator) manages all domains
+ */
+type adminScope struct {
+	usersId      int
+	domainsId    int
+	isSuperAdmin bool
+}
+
+/* Getting scope of administrator from context of request */
+func (r *AdminPostgres) getScope(c *gin.Context) (adminScope, error) {
+	usersId, ok := c.Get(middlewareConstants.USER_CTX)
+	if !ok {
+		return adminScope{}, errors.New("user id not found")
+	}
+
+	domainsId, ok := c.Get(middlewareConstants.DOMAINS_ID)
+	if !ok {
+		return adminScope{}, errors.New("domain not found")
+	}
+
+	scope := adminScope{
+		usersId:   usersId.(int),
+		domainsId: domainsId.(int),
+	}
+
+	serverDomain, err := r.domain.GetDomain("value", viper.GetString("domain"))
+	scope.isSuperAdmin = err == nil && serverDomain.Id == scope.domainsId
+
+	return scope, nil
+}
+
+/* Checking that administrator can manage the domain */
+func (s adminScope) canManage(domainsId *int) bool {
+	return s.isSuperAdmin || (domainsId != nil && *domainsId == s.domainsId)
+}
+
+var errAdminForbidden = errors.New("Нет доступа к данному домену!")
+
+/* Create new domain */
+func (r *AdminPostgres) CreateDomain(c *gin.Context, data rbacModel.DomainCreateRequestModel) (rbacModel.DomainModel, error) {
+	scope, err := r.getScope(c)
+	if err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	// Создание доменов доступно только супер-администратору
+	if !scope.isSuperAdmin {
+		return rbacModel.DomainModel{}, errAdminForbidden
+	}
+
+	var domain rbacModel.DomainModel
+
+	query := fmt.Sprintf("INSERT INTO %s (uuid, value, description, users_id) values ($1, $2, $3, $4) RETURNING *",
+		tableConstants.DOMAINS_TABLE,
+	)
+
+	err = r.db.Get(&domain, query, uuid.NewV4(), data.Value, data.Description, scope.usersId)
+	if err != nil {
+		return rbacModel.DomainModel{}, errors.New("Домен с данным значением уже существует!")
+	}
+
+	return domain, nil
+}
+
+/* Update domain */
+func (r *AdminPostgres) UpdateDomain(c *gin.Context, data rbacModel.DomainUpdateRequestModel) (rbacModel.DomainModel, error) {
+	domain, err := r.getDomain(c, data.Uuid)
+	if err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	// Домен текущего серверного приложения не может быть переименован
+	if domain.Value == viper.GetString("domain") && data.Value != domain.Value {
+		return rbacModel.DomainModel{}, errors.New("Нельзя изменить значение домена серверного приложения!")
+	}
+
+	query := fmt.Sprintf("UPDATE %s tl SET value=$1, description=$2 WHERE tl.id = $3 RETURNING *",
+		tableConstants.DOMAINS_TABLE,
+	)
+
+	err = r.db.Get(&domain, query, data.Value, data.Description, domain.Id)
+	if err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	return domain, nil
+}
+
+/* Delete domain together with its roles and policies */
+func (r *AdminPostgres) DeleteDomain(c *gin.Context, data rbacModel.DomainUuidModel) (rbacModel.RbacSuccessModel, error) {
+	scope, err := r.getScope(c)
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	// Удаление доменов доступно только супер-администратору
+	if !scope.isSuperAdmin {
+		return rbacModel.RbacSuccessModel{}, errAdminForbidden
+	}
+
+	domain, err := r.domain.GetDomain("uuid", data.Uuid)
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, errors.New("Домен не найден!")
+	}
+
+	if domain.Value == viper.GetString("domain") {
+		return rbacModel.RbacSuccessModel{}, errors.New("Нельзя удалить домен серверного приложения!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.domains_id = $1", tableConstants.ROLES_TABLE)
+
+	_, err = tx.Exec(query, domain.Id)
+	if err != nil {
+		tx.Rollback()
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = $1", tableConstants.DOMAINS_TABLE)
+
+	_, err = tx.Exec(query, domain.Id)
+	if err != nil {
+		tx.Rollback()
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	domainId := strconv.Itoa(domain.Id)
+
+	// Удаление назначенных в домене ролей и политик доступа (после фиксации транзакции,
+	// т.к. изменения политики не отменяются откатом транзакции; домен находится на третьей и второй позиции правил)
+	_, err = r.enforcer.RemoveFilteredGroupingPolicy(2, domainId)
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	_, err = r.enforcer.RemoveFilteredPolicy(1, domainId)
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	return rbacModel.RbacSuccessModel{
+		Success: true,
+	}, nil
+}
+
+/* Get domain */
+func (r *AdminPostgres) GetDomain(c *gin.Context, data rbacModel.DomainUuidModel) (rbacModel.DomainModel, error) {
+	return r.getDomain(c, data.Uuid)
+}
+
+/* Get all domains (administrator of domain gets only his domain) */
+func (r *AdminPostgres) GetDomains(c *gin.Context) (rbacModel.DomainsModel, error) {
+	scope, err := r.getScope(c)
+	if err != nil {
+		return rbacModel.DomainsModel{}, err
+	}
+
+	domains := rbacModel.DomainsModel{
+		Domains: []rbacModel.DomainModel{},
+	}
+
+	if scope.isSuperAdmin {
+		query := fmt.Sprintf("SELECT * FROM %s tl ORDER BY tl.id", tableConstants.DOMAINS_TABLE)
+		err = r.db.Select(&domains.Domains, query)
+	} else {
+		query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.id = $1", tableConstants.DOMAINS_TABLE)
+		err = r.db.Select(&domains.Domains, query, scope.domainsId)
+	}
+
+	if err != nil {
+		return rbacModel.DomainsModel{}, err
+	}
+
+	return domains, nil
+}
+
+/* Create new role in domain */
+func (r *AdminPostgres) CreateRole(c *gin.Context, data rbacModel.RoleCreateRequestModel) (rbacModel.RoleModel, error) {
+	scope, err := r.getScope(c)
+	if err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	domain, err := r.getDomain(c, data.DomainUuid)
+	if err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	var role rbacModel.RoleModel
+
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.value = $1 AND tl.domains_id = $2 LIMIT 1", tableConstants.ROLES_TABLE)
+
+	err = r.db.Get(&role, query, data.Value, domain.Id)
+	if err == nil {
+		return rbacModel.RoleModel{}, errors.New("Роль с данным значением уже существует в домене!")
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (uuid, value, description, users_id, domains_id) values ($1, $2, $3, $4, $5) RETURNING *",
+		tableConstants.ROLES_TABLE,
+	)
+
+	err = r.db.Get(&role, query, uuid.NewV4(), data.Value, data.Description, scope.usersId, domain.Id)
+	if err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	return role, nil
+}
+
+/* Update role */
+func (r *AdminPostgres) UpdateRole(c *gin.Context, data rbacModel.RoleUpdateRequestModel) (rbacModel.RoleModel, error) {
+	role, err := r.getRole(c, data.Uuid)
+	if err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	// Системные роли используются серверным приложением по значению
+	if isSystemRole(role.Value) && data.Value != role.Value {
+		return rbacModel.RoleModel{}, errors.New("Нельзя изменить значение системной роли!")
+	}
+
+	query := fmt.Sprintf("UPDATE %s tl SET value=$1, description=$2 WHERE tl.id = $3 RETURNING *",
+		tableConstants.ROLES_TABLE,
+	)
+
+	err = r.db.Get(&role, query, data.Value, data.Description, role.Id)
+	if err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	return role, nil
+}
+
+/* Delete role together with its assignments */
+func (r *AdminPostgres) DeleteRole(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RbacSuccessModel, error) {
+	role, err := r.getRole(c, data.Uuid)
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	if isSystemRole(role.Value) {
+		return rbacModel.RbacSuccessModel{}, errors.New("Нельзя удалить системную роль!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = $1", tableConstants.ROLES_TABLE)
+
+	_, err = tx.Exec(query, role.Id)
+	if err != nil {
+		tx.Rollback()
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	// Удаление назначений роли пользователям (после фиксации транзакции; роль находится на второй позиции правила)
+	_, err = r.enforcer.RemoveFilteredGroupingPolicy(1, strconv.Itoa(role.Id))
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	return rbacModel.RbacSuccessModel{
+		Success: true,
+	}, nil
+}
+
+/* Get role */
+func (r *AdminPostgres) GetRole(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RoleModel, error) {
+	return r.getRole(c, data.Uuid)
+}
+
+/* Get all roles (or roles of domain, administrator of domain gets only roles of his domain) */
+func (r *AdminPostgres) GetRoles(c *gin.Context, data rbacModel.RolesRequestModel) (rbacModel.RolesModel, error) {
+	scope, err := r.getScope(c)
+	if err != nil {
+		return rbacModel.RolesModel{}, err
+	}
+
+	roles := rbacModel.RolesModel{
+		Roles: []rbacModel.RoleModel{},
+	}
+
+	if data.DomainUuid != nil || !scope.isSuperAdmin {
+		domainsId := scope.domainsId
+
+		if data.DomainUuid != nil {
+			domain, err := r.getDomain(c, *data.DomainUuid)
+			if err != nil {
+				return rbacModel.RolesModel{}, err
+			}
+
+			domainsId = domain.Id
+		}
+
+		query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.domains_id = $1 ORDER BY tl.id", tableConstants.ROLES_TABLE)
+		err = r.db.Select(&roles.Roles, query, domainsId)
+	} else {
+		query := fmt.Sprintf("SELECT * FROM %s tl ORDER BY tl.id", tableConstants.ROLES_TABLE)
+		err = r.db.Select(&roles.Roles, query)
+	}
+
+	if err != nil {
+		return rbacModel.RolesModel{}, err
+	}
+
+	return roles, nil
+}
+
+/* Assign role to user in domain of role */
+func (r *AdminPostgres) AssignRole(c *gin.Context, data rbacModel.RoleAssignRequestModel) (rbacModel.RbacSuccessModel, error) {
+	userId, roleId, domainId, err := r.getRoleAssignment(c, data)
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	added, err := r.enforcer.AddRoleForUserInDomain(userId, roleId, domainId)
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	if !added {
+		return rbacModel.RbacSuccessModel{}, errors.New("Пользователю уже назначена данная роль!")
+	}
+
+	return rbacModel.RbacSuccessModel{
+		Success: true,
+	}, nil
+}
+
+/* Remove role from user in domain of role */
+func (r *AdminPostgres) RemoveRole(c *gin.Context, data rbacModel.RoleAssignRequestModel) (rbacModel.RbacSuccessModel, error) {
+	userId, roleId, domainId, err := r.getRoleAssignment(c, data)
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	removed, err := r.enforcer.DeleteRoleForUserInDomain(userId, roleId, domainId)
+	if err != nil {
+		return rbacModel.RbacSuccessModel{}, err
+	}
+
+	if !removed {
+		return rbacModel.RbacSuccessModel{}, errors.New("Пользователю не назначена данная роль!")
+	}
+
+	return rbacModel.RbacSuccessModel{
+		Success: true,
+	}, nil
+}
+
+/* Get users who have role in its domain */
+func (r *AdminPostgres) GetRoleUsers(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RoleUsersModel, error) {
+	role, err := r.getRole(c, data.Uuid)
+	if err != nil {
+		return rbacModel.RoleUsersModel{}, err
+	}
+
+	result := rbacModel.RoleUsersModel{
+		Role:  role,
+		Users: []rbacModel.RoleUserModel{},
+	}
+
+	if role.DomainsId == nil {
+		return result, nil
+	}
+
+	ids := make([]int, 0)
+	for _, element := range r.enforcer.GetUsersForRoleInDomain(strconv.Itoa(role.Id), strconv.Itoa(*role.DomainsId)) {
+		id, err := strconv.Atoi(element)
+		if err != nil {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	if len(ids) <= 0 {
+		return result, nil
+	}
+
+	query := fmt.Sprintf(`SELECT u.id, u.uuid, u.email, ud.data->>'nickname' AS nickname FROM %s u
+		LEFT JOIN %s ud ON ud.users_id = u.id WHERE u.id = ANY($1) ORDER BY u.email`,
+		tableConstants.USERS_TABLE, tableConstants.USERS_DATA_TABLE,
+	)
+
+	err = r.db.Select(&result.Users, query, pq.Array(ids))
+	if err != nil {
+		return rbacModel.RoleUsersModel{}, err
+	}
+
+	return result, nil
+}
+
+/* Get domain by UUID (only domain, which is managed by administrator) */
+func (r *AdminPostgres) getDomain(c *gin.Context, domainUuid string) (rbacModel.DomainModel, error) {
+	scope, err := r.getScope(c)
+	if err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	domain, err := r.domain.GetDomain("uuid", domainUuid)
+	if err != nil {
+		return rbacModel.DomainModel{}, errors.New("Домен не найден!")
+	}
+
+	if !scope.canManage(&domain.Id) {
+		return rbacModel.DomainModel{}, errAdminForbidden
+	}
+
+	return domain, nil
+}
+
+/* Get role by UUID (only role of domain, which is managed by administrator) */
+func (r *AdminPostgres) getRole(c *gin.Context, roleUuid string) (rbacModel.RoleModel, error) {
+	scope, err := r.getScope(c)
+	if err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	var role rbacModel.RoleModel
+
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.uuid = $1 LIMIT 1", tableConstants.ROLES_TABLE)
+
+	err = r.db.Get(&role, query, roleUuid)
+	if err != nil {
+		return rbacModel.RoleModel{}, errors.New("Роль не найдена!")
+	}
+
+	if !scope.canManage(role.DomainsId) {
+		return rbacModel.RoleModel{}, errAdminForbidden
+	}
+
+	return role, nil
+}
+
+/* Get identifiers of user, role and domain of role for assignment */
+func (r *AdminPostgres) getRoleAssignment(c *gin.Context, data rbacModel.RoleAssignRequestModel) (string, string, string, error) {
+	role, err := r.getRole(c, data.RoleUuid)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if role.DomainsId == nil {
+		return "", "", "", errors.New("Роль не принадлежит ни одному домену!")
+	}
+
+	var usersId int
+
+	query := fmt.Sprintf("SELECT tl.id FROM %s tl WHERE tl.uuid = $1 LIMIT 1", tableConstants.USERS_TABLE)
+
+	err = r.db.Get(&usersId, query, data.UserUuid)
+	if err != nil {
+		return "", "", "", errors.New("Пользователь не найден!")
+	}
+
+	return strconv.Itoa(usersId), strconv.Itoa(role.Id), strconv.Itoa(*role.DomainsId), nil
+}
+
+/*
+* Force logout of user: all sessions are removed and all issued access tokens are revoked
+* (administrator of domain can log out only users, who have roles in his domain only)
+ */
+func (r *AdminPostgres) ForceLogout(c *gin.Context, data rbacModel.UserUuidModel) (userModel.SessionRevokeModel, error) {
+	scope, err := r.getScope(c)
+	if err != nil {
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	var usersId int
+
+	query := fmt.Sprintf("SELECT tl.id FROM %s tl WHERE tl.uuid::text = $1 LIMIT 1", tableConstants.USERS_TABLE)
+
+	err = r.db.Get(&usersId, query, data.UserUuid)
+	if err != nil {
+		return userModel.SessionRevokeModel{}, errors.New("Пользователь не найден!")
+	}
+
+	if !scope.isSuperAdmin {
+		grouping := r.enforcer.GetFilteredGroupingPolicy(0, strconv.Itoa(usersId))
+		if len(grouping) <= 0 {
+			return userModel.SessionRevokeModel{}, errAdminForbidden
+		}
+
+		// Сессии пользователя общие для всех доменов (домен находится на третьей позиции правила)
+		for _, rule := range grouping {
+			if len(rule) < 3 || rule[2] != strconv.Itoa(scope.domainsId) {
+				return userModel.SessionRevokeModel{}, errAdminForbidden
+			}
+		}
+	}
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	count, err := revokeUserTokens(tx, usersId)
+	if err != nil {
+		tx.Rollback()
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	return userModel.SessionRevokeModel{
+		Count: count,
+	}, nil
+}
+
+/* Roles used by the server application by their values */
+func isSystemRole(value string) bool {
+	return value == roleConstant.ROLE_USER || value == roleConstant.ROLE_MODERATOR || value == roleConstant.ROLE_ADMIN
+}
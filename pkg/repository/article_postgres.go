@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	articleConstant "main-server/pkg/constant/article"
+	tableConstants "main-server/pkg/constant/table"
+	articleModel "main-server/pkg/model/article"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+/*
+* Condition for articles that have no moderator decision since their last update
+* (the alias of the articles table must be a1)
+ */
+var uncheckedArticleCondition = fmt.Sprintf(
+	"not exists(SELECT * FROM %s AS a2 WHERE a2.articles_id = a1.id AND a2.created_at >= a1.updated_at)",
+	tableConstants.ARTICLES_CHECKED_TABLE,
+)
+
+/*
+* Condition for articles approved by moderator after their last update
+* (the alias of the articles table must be a1)
+ */
+var approvedArticleCondition = fmt.Sprintf(
+	"exists(SELECT * FROM %s AS a2 WHERE a2.articles_id = a1.id AND a2.created_at >= a1.updated_at AND a2.is_approved = true)",
+	tableConstants.ARTICLES_CHECKED_TABLE,
+)
+
+/*
+* Conditions for articles not moved to trash and for articles in trash
+* (the alias of the articles table must be a1)
+ */
+const (
+	activeArticleCondition  = "a1.deleted_at IS NULL"
+	deletedArticleCondition = "a1.deleted_at IS NOT NULL"
+)
+
+/*
+* Condition for articles of the domain of request
+* (the alias of the articles table must be a1, ID of domain must be the first argument)
+ */
+const domainArticleCondition = "a1.domains_id = $1"
+
+/*
+* Condition for published articles visible to guests
+* (the alias of the articles table must be a1)
+ */
+var publishedArticleCondition = fmt.Sprintf("%s AND a1.status = '%s' AND %s",
+	activeArticleCondition,
+	articleConstant.STATUS_PUBLISHED,
+	approvedArticleCondition,
+)
+
+/*
+* Condition for articles submitted for review and waiting for moderator decision
+* (the alias of the articles table must be a1)
+ */
+var reviewArticleCondition = fmt.Sprintf("%s AND a1.status = '%s' AND %s",
+	activeArticleCondition,
+	articleConstant.STATUS_REVIEW,
+	uncheckedArticleCondition,
+)
+
+/*
+* Query for getting articles with public nickname of author
+ */
+var articlesAuthorQuery = fmt.Sprintf(
+	`SELECT a1.*, ud.data->>'nickname' AS author FROM %s AS a1
+	LEFT JOIN %s AS ud ON ud.users_id = a1.users_id`,
+	tableConstants.ARTICLES_TABLE,
+	tableConstants.USERS_DATA_TABLE,
+)
+
+/*
+* Getting a page of articles satisfying the conditions
+* (keyset pagination by created_at and id, conditions use the alias a1 and arguments args)
+ */
+func getArticlesPage(db *sqlx.DB, conditions []string, args []interface{}, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = articleConstant.ARTICLES_LIMIT_DEFAULT
+	} else if limit > articleConstant.ARTICLES_LIMIT_MAX {
+		limit = articleConstant.ARTICLES_LIMIT_MAX
+	}
+
+	order := strings.ToLower(filter.Order)
+	if order == "" {
+		order = articleConstant.ORDER_DESC
+	}
+
+	if order != articleConstant.ORDER_ASC && order != articleConstant.ORDER_DESC {
+		return articleModel.ArticlesModel{}, errors.New("Некорректный порядок сортировки статей!")
+	}
+
+	argId := len(args) + 1
+
+	// Фильтрация по дате создания статьи
+	if filter.DateFrom != nil {
+		conditions = append(conditions, fmt.Sprintf("a1.created_at >= $%d", argId))
+		args = append(args, *filter.DateFrom)
+		argId++
+	}
+
+	if filter.DateTo != nil {
+		conditions = append(conditions, fmt.Sprintf("a1.created_at <= $%d", argId))
+		args = append(args, *filter.DateTo)
+		argId++
+	}
+
+	// Фильтрация по тегам (статья должна содержать все указанные теги)
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, fmt.Sprintf(
+			"exists(SELECT * FROM %s AS at JOIN %s AS tg ON tg.id = at.tags_id WHERE at.articles_id = a1.id AND tg.value = $%d)",
+			tableConstants.ARTICLES_TAGS_TABLE, tableConstants.TAGS_TABLE, argId,
+		))
+		args = append(args, normalizeTag(tag))
+		argId++
+	}
+
+	where := "true"
+	if len(conditions) > 0 {
+		where = strings.Join(conditions, " AND ")
+	}
+
+	// Общее количество статей без учёта курсора
+	var total int
+
+	query := fmt.Sprintf("SELECT count(*) FROM %s AS a1 WHERE %s", tableConstants.ARTICLES_TABLE, where)
+	err := db.Get(&total, query, args...)
+	if err != nil {
+		return articleModel.ArticlesModel{}, err
+	}
+
+	comparison := "<"
+	if order == articleConstant.ORDER_ASC {
+		comparison = ">"
+	}
+
+	if filter.Cursor != nil && *filter.Cursor != "" {
+		createdAt, id, err := decodeArticlesCursor(*filter.Cursor)
+		if err != nil {
+			return articleModel.ArticlesModel{}, err
+		}
+
+		where = fmt.Sprintf("%s AND (a1.created_at, a1.id) %s ($%d, $%d)", where, comparison, argId, argId+1)
+		args = append(args, createdAt, id)
+		argId += 2
+	}
+
+	// Получение на одну запись больше для определения наличия следующей страницы
+	query = fmt.Sprintf("%s WHERE %s ORDER BY a1.created_at %s, a1.id %s LIMIT %d",
+		articlesAuthorQuery, where, order, order, limit+1,
+	)
+
+	var articlesDb []articleModel.ArticleAuthorDBModel
+	err = db.Select(&articlesDb, query, args...)
+	if err != nil {
+		return articleModel.ArticlesModel{}, err
+	}
+
+	var nextCursor *string = nil
+	if len(articlesDb) > limit {
+		articlesDb = articlesDb[:limit]
+		last := articlesDb[limit-1]
+		cursor := encodeArticlesCursor(last.CreatedAt, last.Id)
+		nextCursor = &cursor
+	}
+
+	articles := articleModel.ArticlesModel{
+		Articles:   []articleModel.ArticleModel{},
+		NextCursor: nextCursor,
+		Total:      total,
+	}
+
+	// Получение файлов всех статей страницы одним запросом
+	ids := make([]int, 0, len(articlesDb))
+	for _, element := range articlesDb {
+		ids = append(ids, element.Id)
+	}
+
+	files, err := getArticlesFiles(db, ids)
+	if err != nil {
+		return articleModel.ArticlesModel{}, err
+	}
+
+	for _, element := range articlesDb {
+		articles.Articles = append(articles.Articles, articleModel.ArticleModel{
+			Uuid:      element.Uuid,
+			Filepath:  element.Filepath,
+			Title:     element.Title,
+			Text:      element.Text,
+			Tags:      element.Tags,
+			Files:     files[element.Id],
+			Status:    element.Status,
+			PublishAt: element.PublishAt,
+			DeletedAt: element.DeletedAt,
+			CreatedAt: element.CreatedAt,
+			UpdatedAt: element.UpdatedAt,
+			Author:    element.Author,
+		})
+	}
+
+	return articles, nil
+}
+
+/* Getting files of several articles grouped by ID of article */
+func getArticlesFiles(db *sqlx.DB, articlesIds []int) (map[int][]articleModel.ArticlesFilesDBModel, error) {
+	result := make(map[int][]articleModel.ArticlesFilesDBModel, len(articlesIds))
+
+	if len(articlesIds) <= 0 {
+		return result, nil
+	}
+
+	query := fmt.Sprintf(`SELECT af.articles_id, af.index, f.filename, f.filepath FROM %s AS af 
+		JOIN %s AS f ON af.files_id = f.id WHERE af.articles_id = ANY($1) ORDER BY af.articles_id, af.index`,
+		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
+	)
+
+	var files []articleModel.ArticleFileDBModel
+
+	err := db.Select(&files, query, pq.Array(articlesIds))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, element := range files {
+		result[element.ArticlesId] = append(result[element.ArticlesId], element.ArticlesFilesDBModel)
+	}
+
+	return result, nil
+}
+
+/* Encoding position of article in list into opaque cursor */
+func encodeArticlesCursor(createdAt time.Time, id int) string {
+	value := fmt.Sprintf("%s|%d", createdAt.Format(time.RFC3339Nano), id)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+/* Decoding cursor into position of article in list */
+func decodeArticlesCursor(cursor string) (time.Time, int, error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, errors.New("Некорректный курсор!")
+	}
+
+	parts := strings.Split(string(value), "|")
+	if len(parts) != 2 {
+		return time.Time{}, 0, errors.New("Некорректный курсор!")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, 0, errors.New("Некорректный курсор!")
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return time.Time{}, 0, errors.New("Некорректный курсор!")
+	}
+
+	return createdAt, id, nil
+}
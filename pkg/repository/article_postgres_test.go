@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	articleConstant "main-server/pkg/constant/article"
+	tableConstants "main-server/pkg/constant/table"
+	articleModel "main-server/pkg/model/article"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	benchArticlesCount       = 3000 // Количество статей, файлы которых загружаются одним запросом
+	benchArticleFilesCount   = 3    // Количество файлов каждой статьи
+	benchArticlesDSNVariable = "MAIN_SERVER_BENCH_DSN"
+)
+
+/* Position of article is restored from cursor without loss of precision and time zone */
+func TestArticlesCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		id        int
+	}{
+		{name: "utc", createdAt: time.Date(2022, 6, 1, 12, 30, 0, 0, time.UTC), id: 1},
+		{name: "nanoseconds", createdAt: time.Date(2022, 6, 1, 12, 30, 0, 123456789, time.UTC), id: 42},
+		{name: "time zone", createdAt: time.Date(2022, 6, 1, 15, 30, 0, 0, time.FixedZone("MSK", 3*60*60)), id: 7},
+		{name: "large id", createdAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), id: 1<<31 - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			createdAt, id, err := decodeArticlesCursor(encodeArticlesCursor(tt.createdAt, tt.id))
+			if err != nil {
+				t.Fatalf("decodeArticlesCursor() error = %v", err)
+			}
+
+			if !createdAt.Equal(tt.createdAt) || id != tt.id {
+				t.Errorf("decodeArticlesCursor() = (%v, %d), want (%v, %d)", createdAt, id, tt.createdAt, tt.id)
+			}
+		})
+	}
+}
+
+/* Malformed cursors are rejected */
+func TestDecodeArticlesCursorMalformed(t *testing.T) {
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "empty", cursor: ""},
+		{name: "not base64", cursor: "!!!"},
+		{name: "padded base64", cursor: base64.URLEncoding.EncodeToString([]byte("2022-06-01T12:30:00Z|1"))},
+		{name: "no separator", cursor: encode("2022-06-01T12:30:00Z")},
+		{name: "extra part", cursor: encode("2022-06-01T12:30:00Z|1|2")},
+		{name: "invalid time", cursor: encode("yesterday|1")},
+		{name: "invalid id", cursor: encode("2022-06-01T12:30:00Z|one")},
+		{name: "empty id", cursor: encode("2022-06-01T12:30:00Z|")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodeArticlesCursor(tt.cursor); err == nil {
+				t.Errorf("decodeArticlesCursor(%q) error = nil, want error", tt.cursor)
+			}
+		})
+	}
+}
+
+/*
+* Loading of files of a few thousand articles by one query ("batched")
+* compared with the former query per article ("per_article")
+* (requires migrated Postgres database with at least one user and domain, DSN is set by MAIN_SERVER_BENCH_DSN;
+* seeded articles and files are removed after benchmark)
+ */
+func BenchmarkGetArticlesFiles(b *testing.B) {
+	dsn := os.Getenv(benchArticlesDSNVariable)
+	if dsn == "" {
+		b.Skipf("%s is not set", benchArticlesDSNVariable)
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// Соединение закрывается после удаления созданных статей (cleanup выполняются в обратном порядке)
+	b.Cleanup(func() { db.Close() })
+
+	articlesIds := seedBenchArticles(b, db)
+
+	b.Run("per_article", func(b *testing.B) {
+		query := fmt.Sprintf(`SELECT index, filename, filepath FROM %s JOIN %s ON %s.files_id = %s.id WHERE %s.articles_id=$1;`,
+			tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
+			tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
+			tableConstants.ARTICLES_FILES_TABLE,
+		)
+
+		for i := 0; i < b.N; i++ {
+			files := make(map[int][]articleModel.ArticlesFilesDBModel, len(articlesIds))
+
+			for _, articleId := range articlesIds {
+				var articlesFiles []articleModel.ArticlesFilesDBModel
+
+				if err := db.Select(&articlesFiles, query, articleId); err != nil {
+					b.Fatal(err)
+				}
+
+				files[articleId] = articlesFiles
+			}
+
+			if len(files) != len(articlesIds) {
+				b.Fatalf("files of %d articles are loaded, expected %d", len(files), len(articlesIds))
+			}
+		}
+	})
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			files, err := getArticlesFiles(db, articlesIds)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if len(files) != len(articlesIds) {
+				b.Fatalf("files of %d articles are loaded, expected %d", len(files), len(articlesIds))
+			}
+		}
+	})
+}
+
+/* Creating of articles with files for benchmark (removed by cleanup of benchmark) */
+func seedBenchArticles(b *testing.B, db *sqlx.DB) []int {
+	var usersId int
+	query := fmt.Sprintf("SELECT tl.id FROM %s tl ORDER BY tl.id LIMIT 1", tableConstants.USERS_TABLE)
+
+	if err := db.Get(&usersId, query); err != nil {
+		b.Skipf("database has no users for articles of benchmark: %s", err.Error())
+	}
+
+	var domainsId int
+	query = fmt.Sprintf("SELECT tl.id FROM %s tl ORDER BY tl.id LIMIT 1", tableConstants.DOMAINS_TABLE)
+
+	if err := db.Get(&domainsId, query); err != nil {
+		b.Skipf("database has no domains for articles of benchmark: %s", err.Error())
+	}
+
+	articlesIds := make([]int, 0, benchArticlesCount)
+	filesIds := make([]int, 0, benchArticlesCount*benchArticleFilesCount)
+
+	b.Cleanup(func() {
+		query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.articles_id = ANY($1)", tableConstants.ARTICLES_FILES_TABLE)
+		db.Exec(query, pq.Array(articlesIds))
+
+		query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = ANY($1)", tableConstants.FILES_TABLE)
+		db.Exec(query, pq.Array(filesIds))
+
+		query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = ANY($1)", tableConstants.ARTICLES_TABLE)
+		db.Exec(query, pq.Array(articlesIds))
+	})
+
+	tx, err := db.Beginx()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	currentDate := time.Now()
+
+	for i := 0; i < benchArticlesCount; i++ {
+		var articleId int
+		query := fmt.Sprintf(`INSERT INTO %s (uuid, users_id, domains_id, title, filename, filepath, text, tags, created_at, updated_at, status)
+			values ($1, $2, $3, $4, '', '', '', '', $5, $5, $6) RETURNING id`, tableConstants.ARTICLES_TABLE)
+
+		err := tx.Get(&articleId, query, uuid.NewV4().String(), usersId, domainsId, fmt.Sprintf("benchmark %d", i), currentDate, articleConstant.STATUS_DRAFT)
+		if err != nil {
+			tx.Rollback()
+			b.Fatal(err)
+		}
+
+		articlesIds = append(articlesIds, articleId)
+
+		for index := 0; index < benchArticleFilesCount; index++ {
+			var fileId int
+			query = fmt.Sprintf("INSERT INTO %s (filename, filepath) values ($1, $2) RETURNING id", tableConstants.FILES_TABLE)
+
+			name := fmt.Sprintf("benchmark_%d_%d.png", articleId, index)
+			if err := tx.Get(&fileId, query, name, "public/benchmark/"+name); err != nil {
+				tx.Rollback()
+				b.Fatal(err)
+			}
+
+			filesIds = append(filesIds, fileId)
+
+			query = fmt.Sprintf("INSERT INTO %s (articles_id, files_id, index) values ($1, $2, $3)", tableConstants.ARTICLES_FILES_TABLE)
+			if _, err := tx.Exec(query, articleId, fileId, index); err != nil {
+				tx.Rollback()
+				b.Fatal(err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		articlesIds, filesIds = articlesIds[:0], filesIds[:0]
+		b.Fatal(err)
+	}
+
+	return articlesIds
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"main-server/pkg/constant"
+	tableConstants "main-server/pkg/constant/table"
+	articleModel "main-server/pkg/model/article"
+	util "main-server/pkg/util"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/*
+* Saving the current state of article (content and set of files) as a new immutable revision
+ */
+func saveArticleRevision(tx *sql.Tx, articlesId int, usersId interface{}) error {
+	query := fmt.Sprintf(`INSERT INTO %[1]s (articles_id, version, users_id, title, text, tags, filename, filepath, files, created_at)
+		SELECT a.id, coalesce((SELECT max(r.version) FROM %[1]s r WHERE r.articles_id = a.id), 0) + 1, $2,
+			a.title, a.text, a.tags, a.filename, a.filepath,
+			coalesce((SELECT json_agg(json_build_object('index', af.index, 'filename', f.filename, 'filepath', f.filepath) ORDER BY af.index)
+				FROM %[3]s af JOIN %[4]s f ON f.id = af.files_id WHERE af.articles_id = a.id), '[]'),
+			$3
+		FROM %[2]s a WHERE a.id = $1`,
+		tableConstants.ARTICLES_REVISIONS_TABLE, tableConstants.ARTICLES_TABLE,
+		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
+	)
+
+	_, err := tx.Exec(query, articlesId, usersId, time.Now())
+
+	return err
+}
+
+/* Getting list of revisions of article */
+func getArticleRevisions(db *sqlx.DB, articlesId int) ([]articleModel.ArticleRevisionShortModel, error) {
+	query := fmt.Sprintf("SELECT version, title, created_at FROM %s tl WHERE tl.articles_id = $1 ORDER BY tl.version DESC",
+		tableConstants.ARTICLES_REVISIONS_TABLE,
+	)
+
+	revisions := []articleModel.ArticleRevisionShortModel{}
+
+	err := db.Select(&revisions, query, articlesId)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+/* Getting revision of article by its version (version 0 is an empty article) */
+func getArticleRevision(db *sqlx.DB, articlesId, version int) (articleModel.ArticleRevisionModel, error) {
+	if version == 0 {
+		return articleModel.ArticleRevisionModel{}, nil
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.articles_id = $1 AND tl.version = $2 LIMIT 1",
+		tableConstants.ARTICLES_REVISIONS_TABLE,
+	)
+
+	var revision articleModel.ArticleRevisionDBModel
+
+	err := db.Get(&revision, query, articlesId, version)
+	if err != nil {
+		return articleModel.ArticleRevisionModel{}, err
+	}
+
+	return convertArticleRevision(revision)
+}
+
+/* Getting the last revision of article created not later than the specified time */
+func getArticleRevisionAt(db *sqlx.DB, articlesId int, date time.Time) (articleModel.ArticleRevisionModel, error) {
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.articles_id = $1 AND tl.created_at <= $2 ORDER BY tl.version DESC LIMIT 1",
+		tableConstants.ARTICLES_REVISIONS_TABLE,
+	)
+
+	var revision articleModel.ArticleRevisionDBModel
+
+	err := db.Get(&revision, query, articlesId, date)
+	if err == sql.ErrNoRows {
+		return articleModel.ArticleRevisionModel{}, nil
+	}
+
+	if err != nil {
+		return articleModel.ArticleRevisionModel{}, err
+	}
+
+	return convertArticleRevision(revision)
+}
+
+/* Getting the latest revision of article */
+func getArticleLastRevision(db *sqlx.DB, articlesId int) (articleModel.ArticleRevisionModel, error) {
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.articles_id = $1 ORDER BY tl.version DESC LIMIT 1",
+		tableConstants.ARTICLES_REVISIONS_TABLE,
+	)
+
+	var revision articleModel.ArticleRevisionDBModel
+
+	err := db.Get(&revision, query, articlesId)
+	if err != nil {
+		return articleModel.ArticleRevisionModel{}, err
+	}
+
+	return convertArticleRevision(revision)
+}
+
+/* Getting paths of files from all revisions of article */
+func getArticleRevisionsFilepaths(db *sqlx.DB, articlesId int) ([]string, error) {
+	query := fmt.Sprintf(`SELECT tl.filepath FROM %[1]s tl WHERE tl.articles_id = $1
+		UNION SELECT f->>'filepath' FROM %[1]s tl, jsonb_array_elements(tl.files) f WHERE tl.articles_id = $1`,
+		tableConstants.ARTICLES_REVISIONS_TABLE,
+	)
+
+	var paths []string
+
+	err := db.Select(&paths, query, articlesId)
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+func convertArticleRevision(revision articleModel.ArticleRevisionDBModel) (articleModel.ArticleRevisionModel, error) {
+	var files []articleModel.ArticlesFilesDBModel
+
+	err := json.Unmarshal(revision.Files, &files)
+	if err != nil {
+		return articleModel.ArticleRevisionModel{}, err
+	}
+
+	return articleModel.ArticleRevisionModel{
+		Version:   revision.Version,
+		Title:     revision.Title,
+		Text:      revision.Text,
+		Tags:      revision.Tags,
+		Filename:  revision.Filename,
+		Filepath:  revision.Filepath,
+		Files:     files,
+		CreatedAt: revision.CreatedAt,
+	}, nil
+}
+
+/* Comparison of two revisions of article */
+func diffArticleRevisions(uuid string, from, to articleModel.ArticleRevisionModel) articleModel.ArticleRevisionDiffModel {
+	fromFiles := make(map[int]string)
+	for _, element := range from.Files {
+		fromFiles[element.Index] = element.Filepath
+	}
+
+	toFiles := make(map[int]string)
+	for _, element := range to.Files {
+		toFiles[element.Index] = element.Filepath
+	}
+
+	filesAdded := []int{}
+	for _, element := range to.Files {
+		if path, ok := fromFiles[element.Index]; !ok || path != element.Filepath {
+			filesAdded = append(filesAdded, element.Index)
+		}
+	}
+
+	filesRemoved := []int{}
+	for _, element := range from.Files {
+		if path, ok := toFiles[element.Index]; !ok || path != element.Filepath {
+			filesRemoved = append(filesRemoved, element.Index)
+		}
+	}
+
+	return articleModel.ArticleRevisionDiffModel{
+		Uuid:              uuid,
+		From:              from.Version,
+		To:                to.Version,
+		Title:             util.DiffLines(from.Title, to.Title),
+		Text:              util.DiffLines(from.Text, to.Text),
+		Tags:              util.DiffLines(splitTagsLines(from.Tags), splitTagsLines(to.Tags)),
+		TitleImageChanged: from.Filepath != to.Filepath,
+		FilesAdded:        filesAdded,
+		FilesRemoved:      filesRemoved,
+	}
+}
+
+/* Tags are compared one per line */
+func splitTagsLines(tags string) string {
+	return strings.ReplaceAll(tags, constant.SEPARATOR, "\n")
+}
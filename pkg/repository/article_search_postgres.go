@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"main-server/pkg/constant"
+	articleConstant "main-server/pkg/constant/article"
+	tableConstants "main-server/pkg/constant/table"
+	articleModel "main-server/pkg/model/article"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/*
+* Search document of article (the alias of the articles table must be a1).
+* The expression must match the GIN index articles_search_idx, so Postgres keeps
+* the index current on every insert and update of the articles table
+ */
+var articleSearchDocument = fmt.Sprintf(
+	`(setweight(to_tsvector('%[1]s', coalesce(a1.title, '')), 'A') || `+
+		`setweight(to_tsvector('%[1]s', replace(coalesce(a1.tags, ''), '%[2]s', ' ')), 'B') || `+
+		`setweight(to_tsvector('%[1]s', coalesce(a1.text, '')), 'C'))`,
+	articleConstant.SEARCH_CONFIG, constant.SEPARATOR,
+)
+
+/*
+* Full-text search of articles satisfying the conditions
+* (conditions use the alias a1 and arguments args)
+ */
+func searchArticles(db *sqlx.DB, conditions []string, args []interface{}, search articleModel.ArticlesSearchRequestModel) (articleModel.ArticlesSearchModel, error) {
+	if strings.TrimSpace(search.Query) == "" {
+		return articleModel.ArticlesSearchModel{}, errors.New("Пустой поисковый запрос!")
+	}
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = articleConstant.ARTICLES_LIMIT_DEFAULT
+	} else if limit > articleConstant.ARTICLES_LIMIT_MAX {
+		limit = articleConstant.ARTICLES_LIMIT_MAX
+	}
+
+	offset := search.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	tsQuery := fmt.Sprintf("websearch_to_tsquery('%s', $%d)", articleConstant.SEARCH_CONFIG, len(args)+1)
+	args = append(args, search.Query)
+
+	conditions = append(conditions, fmt.Sprintf("%s @@ %s", articleSearchDocument, tsQuery))
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+
+	query := fmt.Sprintf("SELECT count(*) FROM %s AS a1 WHERE %s", tableConstants.ARTICLES_TABLE, where)
+	err := db.Get(&total, query, args...)
+	if err != nil {
+		return articleModel.ArticlesSearchModel{}, err
+	}
+
+	query = fmt.Sprintf(`SELECT a1.*, ud.data->>'nickname' AS author,
+		ts_rank(%s, %s) AS rank, ts_headline('%s', a1.text, %s, '%s') AS snippet
+		FROM %s AS a1 LEFT JOIN %s AS ud ON ud.users_id = a1.users_id
+		WHERE %s ORDER BY rank DESC, a1.id DESC LIMIT %d OFFSET %d`,
+		articleSearchDocument, tsQuery,
+		articleConstant.SEARCH_CONFIG, tsQuery, articleConstant.SEARCH_HEADLINE_OPTIONS,
+		tableConstants.ARTICLES_TABLE, tableConstants.USERS_DATA_TABLE,
+		where, limit, offset,
+	)
+
+	var articlesDb []articleModel.ArticleSearchDBModel
+	err = db.Select(&articlesDb, query, args...)
+	if err != nil {
+		return articleModel.ArticlesSearchModel{}, err
+	}
+
+	ids := make([]int, 0, len(articlesDb))
+	for _, element := range articlesDb {
+		ids = append(ids, element.Id)
+	}
+
+	files, err := getArticlesFiles(db, ids)
+	if err != nil {
+		return articleModel.ArticlesSearchModel{}, err
+	}
+
+	articles := articleModel.ArticlesSearchModel{
+		Articles: []articleModel.ArticleSearchModel{},
+		Total:    total,
+	}
+
+	for _, element := range articlesDb {
+		articles.Articles = append(articles.Articles, articleModel.ArticleSearchModel{
+			ArticleModel: articleModel.ArticleModel{
+				Uuid:      element.Uuid,
+				Filepath:  element.Filepath,
+				Title:     element.Title,
+				Text:      element.Text,
+				Tags:      element.Tags,
+				Files:     files[element.Id],
+				Status:    element.Status,
+				PublishAt: element.PublishAt,
+				CreatedAt: element.CreatedAt,
+				UpdatedAt: element.UpdatedAt,
+				Author:    element.Author,
+			},
+			Rank:    element.Rank,
+			Snippet: element.Snippet,
+		})
+	}
+
+	return articles, nil
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	authService "main-server/pkg/service/auth"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+/* Rules of throttling of failed attempts (per account and per IP address) */
+type attemptsPolicy struct {
+	scope        string
+	delayBase    time.Duration // Задержка после первой попытки сверх свободных
+	accountFree  int           // Количество попыток по аккаунту без задержки
+	accountLimit int           // Количество попыток по аккаунту до временной блокировки
+	ipFree       int           // Количество попыток с IP-адреса без задержки
+	ipLimit      int           // Количество попыток с IP-адреса до временной блокировки
+	unlock       bool          // Отправка письма для разблокировки аккаунта
+}
+
+var signInAttemptsPolicy = attemptsPolicy{
+	scope:        authConstants.ATTEMPTS_SCOPE_SIGN_IN,
+	delayBase:    authConstants.SIGN_IN_DELAY_BASE,
+	accountFree:  authConstants.SIGN_IN_ACCOUNT_FREE,
+	accountLimit: authConstants.SIGN_IN_ACCOUNT_LIMIT,
+	ipFree:       authConstants.SIGN_IN_IP_FREE,
+	ipLimit:      authConstants.SIGN_IN_IP_LIMIT,
+	unlock:       true,
+}
+
+var recoveryAttemptsPolicy = attemptsPolicy{
+	scope:        authConstants.ATTEMPTS_SCOPE_RECOVERY,
+	delayBase:    authConstants.RECOVERY_DELAY_BASE,
+	accountFree:  authConstants.RECOVERY_ACCOUNT_FREE,
+	accountLimit: authConstants.RECOVERY_ACCOUNT_LIMIT,
+	ipFree:       authConstants.RECOVERY_IP_FREE,
+	ipLimit:      authConstants.RECOVERY_IP_LIMIT,
+}
+
+var activateAttemptsPolicy = attemptsPolicy{
+	scope:        authConstants.ATTEMPTS_SCOPE_ACTIVATE,
+	delayBase:    authConstants.ACTIVATE_DELAY_BASE,
+	accountFree:  authConstants.ACTIVATE_ACCOUNT_FREE,
+	accountLimit: authConstants.ACTIVATE_ACCOUNT_LIMIT,
+	ipFree:       authConstants.ACTIVATE_IP_FREE,
+	ipLimit:      authConstants.ACTIVATE_IP_LIMIT,
+}
+
+var mfaAttemptsPolicy = attemptsPolicy{
+	scope:        authConstants.ATTEMPTS_SCOPE_MFA,
+	delayBase:    authConstants.MFA_DELAY_BASE,
+	accountFree:  authConstants.MFA_ACCOUNT_FREE,
+	accountLimit: authConstants.MFA_ACCOUNT_LIMIT,
+	ipFree:       authConstants.MFA_IP_FREE,
+	ipLimit:      authConstants.MFA_IP_LIMIT,
+}
+
+func (p attemptsPolicy) limits(kind string) (int, int) {
+	if kind == authConstants.ATTEMPTS_KIND_IP {
+		return p.ipFree, p.ipLimit
+	}
+
+	return p.accountFree, p.accountLimit
+}
+
+/* Delay before next attempt (doubled after each failed attempt over free ones) */
+func (p attemptsPolicy) delay(kind string, failures int) time.Duration {
+	free, _ := p.limits(kind)
+	if failures <= free {
+		return 0
+	}
+
+	delay := p.delayBase
+	for i := free + 1; i < failures && delay < authConstants.ATTEMPTS_DELAY_MAX; i++ {
+		delay *= 2
+	}
+
+	if delay > authConstants.ATTEMPTS_DELAY_MAX {
+		delay = authConstants.ATTEMPTS_DELAY_MAX
+	}
+
+	return delay
+}
+
+/* Keys of attempts: account is identified by email (even if it does not exist), client - by IP address */
+func attemptsKeys(email, ip string) map[string]string {
+	keys := map[string]string{
+		authConstants.ATTEMPTS_KIND_ACCOUNT: strings.ToLower(strings.TrimSpace(email)),
+	}
+
+	if ip != "" {
+		keys[authConstants.ATTEMPTS_KIND_IP] = ip
+	}
+
+	return keys
+}
+
+/*
+* Checking that the attempt is allowed: neither account nor IP address is locked
+* and delay after previous failed attempt has passed
+ */
+func checkAttempts(db *sqlx.DB, policy attemptsPolicy, email, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.scope = $1 AND tl.kind = $2 AND tl.value = $3 LIMIT 1", tableConstants.AUTH_ATTEMPTS_TABLE)
+
+	for kind, value := range attemptsKeys(email, ip) {
+		var attempt userModel.AuthAttemptModel
+		if err := db.Get(&attempt, query, policy.scope, kind, value); err != nil {
+			continue
+		}
+
+		var wait time.Duration
+		if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+			wait = attempt.LockedUntil.Sub(now)
+		} else if now.Sub(attempt.LastFailureAt) < authConstants.ATTEMPTS_WINDOW {
+			wait = attempt.LastFailureAt.Add(policy.delay(kind, attempt.Failures)).Sub(now)
+		}
+
+		if wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &authService.ThrottleError{
+			RetryAfter: retryAfter,
+		}
+	}
+
+	return nil
+}
+
+/*
+* Registration of failed attempt for account and IP address (on exceeding of limit they are locked temporarily)
+* (token for unlock is returned, when account has been locked by this attempt)
+ */
+func registerFailedAttempt(db *sqlx.DB, policy attemptsPolicy, email, ip string) (string, error) {
+	now := time.Now()
+
+	// Удаление устаревших записей о попытках
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.last_failure_at < $1 AND (tl.locked_until IS NULL OR tl.locked_until < $2)",
+		tableConstants.AUTH_ATTEMPTS_TABLE,
+	)
+
+	if _, err := db.Exec(query, now.Add(-authConstants.ATTEMPTS_WINDOW), now); err != nil {
+		return "", err
+	}
+
+	unlockToken := ""
+
+	for kind, value := range attemptsKeys(email, ip) {
+		// Счётчик сбрасывается, если предыдущая неудачная попытка была за пределами окна
+		query = fmt.Sprintf(`INSERT INTO %s (scope, kind, value, failures, last_failure_at) values ($1, $2, $3, 1, $4)
+			ON CONFLICT (scope, kind, value) DO UPDATE SET
+			failures = CASE WHEN %s.last_failure_at < $5 THEN 1 ELSE %s.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at
+			RETURNING failures`,
+			tableConstants.AUTH_ATTEMPTS_TABLE, tableConstants.AUTH_ATTEMPTS_TABLE, tableConstants.AUTH_ATTEMPTS_TABLE,
+		)
+
+		var failures int
+		if err := db.QueryRow(query, policy.scope, kind, value, now, now.Add(-authConstants.ATTEMPTS_WINDOW)).Scan(&failures); err != nil {
+			return "", err
+		}
+
+		if _, limit := policy.limits(kind); failures < limit {
+			continue
+		}
+
+		token := ""
+		tokenHash := interface{}(nil)
+		if policy.unlock && kind == authConstants.ATTEMPTS_KIND_ACCOUNT {
+			buf := make([]byte, 32)
+			if _, err := rand.Read(buf); err != nil {
+				return "", err
+			}
+
+			token = hex.EncodeToString(buf)
+			tokenHash = hashToken(token)
+		}
+
+		query = fmt.Sprintf(`UPDATE %s tl SET locked_until = $1, unlock_token_hash = $2
+			WHERE tl.scope = $3 AND tl.kind = $4 AND tl.value = $5 AND (tl.locked_until IS NULL OR tl.locked_until <= $6)`,
+			tableConstants.AUTH_ATTEMPTS_TABLE,
+		)
+
+		result, err := db.Exec(query, now.Add(authConstants.ATTEMPTS_LOCKOUT), tokenHash, policy.scope, kind, value, now)
+		if err != nil {
+			return "", err
+		}
+
+		if count, err := result.RowsAffected(); err == nil && count > 0 && token != "" {
+			unlockToken = token
+		}
+	}
+
+	return unlockToken, nil
+}
+
+/* Resetting of failed attempts of account after successful authentication (attempts of IP address are kept) */
+func resetAttempts(db *sqlx.DB, policy attemptsPolicy, email string) error {
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.scope = $1 AND tl.kind = $2 AND tl.value = $3", tableConstants.AUTH_ATTEMPTS_TABLE)
+
+	_, err := db.Exec(query, policy.scope, authConstants.ATTEMPTS_KIND_ACCOUNT, attemptsKeys(email, "")[authConstants.ATTEMPTS_KIND_ACCOUNT])
+
+	return err
+}
+
+var (
+	dummyPasswordOnce sync.Once
+	dummyPasswordHash []byte
+)
+
+/*
+* Comparing of password with hash of password of user
+* (if user does not exist or has no password, comparing is done with dummy hash,
+* so that time of response does not reveal existence of account)
+ */
+func comparePassword(hash *string, password string) bool {
+	if hash == nil {
+		dummyPasswordOnce.Do(func() {
+			dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), viper.GetInt("crypt.cost"))
+		})
+
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(*hash), []byte(password)) == nil
+}
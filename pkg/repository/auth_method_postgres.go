@@ -0,0 +1,562 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	authService "main-server/pkg/service/auth"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+)
+
+type AuthMethodPostgres struct {
+	db *sqlx.DB
+}
+
+/*
+* Функция создания экземпляра сервиса
+ */
+func NewAuthMethodPostgres(db *sqlx.DB) *AuthMethodPostgres {
+	return &AuthMethodPostgres{
+		db: db,
+	}
+}
+
+/* Получение всех способов входа пользователя */
+func (r *AuthMethodPostgres) GetAuthMethods(usersId int) (userModel.AuthMethodsModel, error) {
+	query := fmt.Sprintf(`SELECT tl.value, ei.email, td.created_at FROM %s tl
+		INNER JOIN %s td on td.auth_types_id = tl.id
+		LEFT JOIN %s ei on ei.users_id = td.users_id AND ei.auth_types_id = td.auth_types_id
+		WHERE td.users_id = $1 ORDER BY td.created_at`,
+		tableConstants.AUTH_TYPES_TABLE, tableConstants.USERS_AUTH_TYPES_TABLE, tableConstants.EXTERNAL_IDENTITIES_TABLE,
+	)
+
+	var methods []userModel.AuthMethodModel
+	if err := r.db.Select(&methods, query, usersId); err != nil {
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	return userModel.AuthMethodsModel{
+		AuthMethods: methods,
+	}, nil
+}
+
+/* Привязка аккаунта внешнего провайдера к пользователю (по коду авторизации провайдера) */
+func (r *AuthMethodPostgres) LinkAuthMethod(usersId int, provider, code string) (userModel.AuthMethodsModel, error) {
+	authProvider, ok := authService.GetProvider(provider)
+	if !ok {
+		return userModel.AuthMethodsModel{}, errors.New("Данный способ авторизации не поддерживается!")
+	}
+
+	ctx := context.Background()
+
+	token, err := authProvider.Exchange(ctx, code)
+	if err != nil {
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	userData, err := authProvider.UserInfo(ctx, token)
+	if err != nil {
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	if _, err := linkIdentity(tx, usersId, provider, userData, token); err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	return r.GetAuthMethods(usersId)
+}
+
+/*
+* Отвязка способа входа от пользователя
+* (последний способ входа отвязать нельзя, сессии, созданные данным способом входа, завершаются)
+ */
+func (r *AuthMethodPostgres) UnlinkAuthMethod(usersId int, value string) (userModel.AuthMethodsModel, error) {
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	// Блокировка пользователя, чтобы параллельные запросы не отвязали все способы входа
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id=$1 FOR UPDATE", tableConstants.USERS_TABLE)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	var authTypesId int
+	query = fmt.Sprintf(`SELECT tl.id FROM %s tl
+		INNER JOIN %s td on td.auth_types_id = tl.id WHERE td.users_id = $1 AND tl.value = $2 LIMIT 1`,
+		tableConstants.AUTH_TYPES_TABLE, tableConstants.USERS_AUTH_TYPES_TABLE,
+	)
+
+	if err := tx.QueryRow(query, usersId, value).Scan(&authTypesId); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return userModel.AuthMethodsModel{}, errors.New("Данный способ входа не привязан к пользователю!")
+		}
+
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	var count int
+	query = fmt.Sprintf("SELECT count(*) FROM %s WHERE users_id = $1", tableConstants.USERS_AUTH_TYPES_TABLE)
+	if err := tx.QueryRow(query, usersId).Scan(&count); err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	if count <= 1 {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, errors.New("Невозможно отвязать единственный способ входа!")
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE users_id = $1 AND auth_types_id = $2", tableConstants.USERS_AUTH_TYPES_TABLE)
+	if _, err := tx.Exec(query, usersId, authTypesId); err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	if _, err := deleteSessions(tx, "tl.users_id = $1 AND tl.auth_types_id = $2", usersId, authTypesId); err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	var identity userModel.ExternalIdentityModel
+	query = fmt.Sprintf("DELETE FROM %s WHERE users_id = $1 AND auth_types_id = $2 RETURNING access_token, refresh_token",
+		tableConstants.EXTERNAL_IDENTITIES_TABLE,
+	)
+
+	err = tx.QueryRow(query, usersId, authTypesId).Scan(&identity.AccessToken, &identity.RefreshToken)
+	if err != nil && err != sql.ErrNoRows {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	// Отзыв доступа, выданного провайдером (ошибка отзыва не отменяет отвязку)
+	revokeIdentityTokens(value, identity)
+
+	return r.GetAuthMethods(usersId)
+}
+
+/* Установка пароля пользователю без локального способа входа */
+func (r *AuthMethodPostgres) SetPassword(usersId int, data userModel.SetPasswordModel) (userModel.AuthMethodsModel, error) {
+	if hasAuthType(r.db, usersId, authConstants.AUTH_TYPE_LOCAL) {
+		return userModel.AuthMethodsModel{}, errors.New("Пароль уже установлен! Воспользуйтесь восстановлением пароля")
+	}
+
+	// Хэширование пароля
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(data.Password), viper.GetInt("crypt.cost"))
+	if err != nil {
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2", tableConstants.USERS_TABLE)
+	if _, err := tx.Exec(query, string(hashedPassword), usersId); err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	if _, err := linkAuthType(tx, usersId, authConstants.AUTH_TYPE_LOCAL); err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return userModel.AuthMethodsModel{}, err
+	}
+
+	return r.GetAuthMethods(usersId)
+}
+
+/*
+* Проверка того, что доступ, выданный внешним провайдером, всё ещё действителен
+* (если токены провайдера не сохранены или аккаунт провайдера привязан только по email-адресу, проверка не выполняется)
+ */
+func (r *AuthMethodPostgres) VerifyAuthMethod(usersId int, value string) (bool, error) {
+	provider, ok := authService.GetProvider(value)
+	if !ok {
+		return true, nil
+	}
+
+	identity, err := getIdentity(r.db, usersId, value)
+	if err == sql.ErrNoRows && hasLegacyAuthType(r.db, usersId, value) {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if identity.AccessToken == nil {
+		return true, nil
+	}
+
+	accessToken, err := authService.DecryptToken(*identity.AccessToken)
+	if err != nil {
+		if err == authService.ErrTokenCipherDisabled {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	return provider.Verify(context.Background(), accessToken)
+}
+
+/*
+* Linking the login method to the user (if it is not linked yet)
+ */
+func linkAuthType(tx *sql.Tx, usersId int, value string) (userModel.AuthTypeModel, error) {
+	var authTypes userModel.AuthTypeModel
+	query := fmt.Sprintf("SELECT id, uuid, value FROM %s WHERE value=$1 LIMIT 1", tableConstants.AUTH_TYPES_TABLE)
+	if err := tx.QueryRow(query, value).Scan(&authTypes.Id, &authTypes.Uuid, &authTypes.Value); err != nil {
+		return userModel.AuthTypeModel{}, errors.New("Данный тип аутентификации не поддерживается!")
+	}
+
+	query = fmt.Sprintf(`INSERT INTO %s (users_id, auth_types_id, created_at) SELECT $1, $2, $3
+		WHERE NOT EXISTS (SELECT 1 FROM %s WHERE users_id = $1 AND auth_types_id = $2)`,
+		tableConstants.USERS_AUTH_TYPES_TABLE, tableConstants.USERS_AUTH_TYPES_TABLE,
+	)
+
+	if _, err := tx.Exec(query, usersId, authTypes.Id, time.Now()); err != nil {
+		return userModel.AuthTypeModel{}, err
+	}
+
+	return authTypes, nil
+}
+
+/*
+* Linking the account of provider to the user with saving of encrypted tokens of provider
+* (account of provider can be linked only to one user, user can have only one account of each provider)
+ */
+func linkIdentity(tx *sql.Tx, usersId int, value string, user userModel.ProviderUserModel, token *oauth2.Token) (userModel.AuthTypeModel, error) {
+	if user.Subject == "" {
+		return userModel.AuthTypeModel{}, errors.New("Провайдер не предоставил идентификатор пользователя!")
+	}
+
+	authTypes, err := linkAuthType(tx, usersId, value)
+	if err != nil {
+		return userModel.AuthTypeModel{}, err
+	}
+
+	var linkedId int
+	var subject string
+	query := fmt.Sprintf("SELECT users_id, subject FROM %s WHERE auth_types_id = $1 AND (subject = $2 OR users_id = $3) LIMIT 1",
+		tableConstants.EXTERNAL_IDENTITIES_TABLE,
+	)
+
+	err = tx.QueryRow(query, authTypes.Id, user.Subject, usersId).Scan(&linkedId, &subject)
+	if err != nil && err != sql.ErrNoRows {
+		return userModel.AuthTypeModel{}, err
+	}
+
+	if err == nil && linkedId != usersId {
+		return userModel.AuthTypeModel{}, errors.New("Данный аккаунт уже привязан к другому пользователю!")
+	}
+
+	if err == nil && subject != user.Subject {
+		return userModel.AuthTypeModel{}, errors.New("К пользователю уже привязан другой аккаунт данного провайдера!")
+	}
+
+	accessToken, err := encryptToken(token.AccessToken)
+	if err != nil {
+		return userModel.AuthTypeModel{}, err
+	}
+
+	refreshToken, err := encryptToken(token.RefreshToken)
+	if err != nil {
+		return userModel.AuthTypeModel{}, err
+	}
+
+	var expiresAt interface{}
+	if !token.Expiry.IsZero() {
+		expiresAt = token.Expiry
+	}
+
+	// Провайдер может не выдать новый токен обновления - сохраняется предыдущий
+	query = fmt.Sprintf(`INSERT INTO %s (users_id, auth_types_id, subject, email, access_token, refresh_token, expires_at, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (users_id, auth_types_id) DO UPDATE SET email = EXCLUDED.email, access_token = EXCLUDED.access_token,
+		refresh_token = COALESCE(EXCLUDED.refresh_token, %s.refresh_token), expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at`,
+		tableConstants.EXTERNAL_IDENTITIES_TABLE, tableConstants.EXTERNAL_IDENTITIES_TABLE,
+	)
+
+	_, err = tx.Exec(query, usersId, authTypes.Id, user.Subject, nullString(user.Email), accessToken, refreshToken, expiresAt, time.Now())
+
+	return authTypes, err
+}
+
+/* Getting account of provider linked to the user */
+func getIdentity(db *sqlx.DB, usersId int, value string) (userModel.ExternalIdentityModel, error) {
+	var identity userModel.ExternalIdentityModel
+	query := fmt.Sprintf(`SELECT ei.* FROM %s ei
+		INNER JOIN %s tl on tl.id = ei.auth_types_id
+		WHERE ei.users_id = $1 AND tl.value = $2 LIMIT 1`,
+		tableConstants.EXTERNAL_IDENTITIES_TABLE, tableConstants.AUTH_TYPES_TABLE,
+	)
+
+	err := db.Get(&identity, query, usersId, value)
+
+	return identity, err
+}
+
+/* Getting the user, to which the account of provider is linked */
+func getUserByIdentity(db *sqlx.DB, value, subject string) (userModel.UserModel, error) {
+	var user userModel.UserModel
+
+	if subject == "" {
+		return user, sql.ErrNoRows
+	}
+
+	query := fmt.Sprintf(`SELECT u.* FROM %s u
+		INNER JOIN %s ei on ei.users_id = u.id
+		INNER JOIN %s tl on tl.id = ei.auth_types_id
+		WHERE tl.value = $1 AND ei.subject = $2 LIMIT 1`,
+		tableConstants.USERS_TABLE, tableConstants.EXTERNAL_IDENTITIES_TABLE, tableConstants.AUTH_TYPES_TABLE,
+	)
+
+	err := db.Get(&user, query, value, subject)
+
+	return user, err
+}
+
+/*
+* Refreshing tokens of provider linked to the user
+* (if provider does not issue refresh tokens, tokens are not stored or provider is linked by email only, nothing is done)
+ */
+func refreshIdentity(db *sqlx.DB, usersId int, value string) error {
+	provider, ok := authService.GetProvider(value)
+	if !ok {
+		return errors.New("Данный способ авторизации не поддерживается!")
+	}
+
+	identity, err := getIdentity(db, usersId, value)
+	if err == sql.ErrNoRows && hasLegacyAuthType(db, usersId, value) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.New("Аккаунт провайдера не привязан к пользователю!")
+	}
+
+	if identity.RefreshToken == nil {
+		return nil
+	}
+
+	refreshToken, err := authService.DecryptToken(*identity.RefreshToken)
+	if err == authService.ErrTokenCipherDisabled {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	token, err := provider.Refresh(context.Background(), refreshToken)
+	if err == authService.ErrRefreshNotSupported {
+		return nil
+	}
+
+	// Если токен провайдера не валиден, то нужно чтобы пользователь перезашёл в приложение заново
+	if err != nil {
+		return err
+	}
+
+	accessToken, err := encryptToken(token.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	newRefreshToken, err := encryptToken(token.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt interface{}
+	if !token.Expiry.IsZero() {
+		expiresAt = token.Expiry
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET access_token=$1, refresh_token=COALESCE($2, refresh_token), expires_at=$3, updated_at=$4
+		WHERE id=$5`,
+		tableConstants.EXTERNAL_IDENTITIES_TABLE,
+	)
+
+	_, err = db.Exec(query, accessToken, newRefreshToken, expiresAt, time.Now(), identity.Id)
+
+	return err
+}
+
+/* Revoking tokens of provider (errors are only logged) */
+func revokeIdentityTokens(value string, identity userModel.ExternalIdentityModel) {
+	provider, ok := authService.GetProvider(value)
+	if !ok {
+		return
+	}
+
+	for _, encrypted := range []*string{identity.RefreshToken, identity.AccessToken} {
+		if encrypted == nil {
+			continue
+		}
+
+		token, err := authService.DecryptToken(*encrypted)
+		if err != nil {
+			continue
+		}
+
+		if err := provider.Revoke(context.Background(), token); err != nil {
+			logrus.Errorf("failed to revoke token of provider %s: %s", value, err.Error())
+		}
+	}
+}
+
+/* Encryption of token of provider (empty token or disabled encryption are stored as NULL) */
+func encryptToken(token string) (interface{}, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	encrypted, err := authService.EncryptToken(token)
+	if err == authService.ErrTokenCipherDisabled {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return encrypted, nil
+}
+
+/* Checking that the login method is linked to the user */
+func hasAuthType(db *sqlx.DB, usersId int, value string) bool {
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s tl
+		INNER JOIN %s td on td.auth_types_id = tl.id WHERE td.users_id = $1 AND tl.value = $2)`,
+		tableConstants.AUTH_TYPES_TABLE, tableConstants.USERS_AUTH_TYPES_TABLE,
+	)
+
+	var exists bool
+	if err := db.Get(&exists, query, usersId, value); err != nil {
+		return false
+	}
+
+	return exists
+}
+
+/*
+* Checking that the login method of provider is linked to the user without account of provider
+* (providers were linked by email only before identifiers of accounts were stored; such link is adopted
+* with identifier of account on the next sign-in through the provider)
+ */
+func hasLegacyAuthType(db *sqlx.DB, usersId int, value string) bool {
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s tl
+		INNER JOIN %s td on td.auth_types_id = tl.id WHERE td.users_id = $1 AND tl.value = $2
+		AND NOT EXISTS (SELECT 1 FROM %s ei WHERE ei.users_id = td.users_id AND ei.auth_types_id = td.auth_types_id))`,
+		tableConstants.AUTH_TYPES_TABLE, tableConstants.USERS_AUTH_TYPES_TABLE, tableConstants.EXTERNAL_IDENTITIES_TABLE,
+	)
+
+	var exists bool
+	if err := db.Get(&exists, query, usersId, value); err != nil {
+		return false
+	}
+
+	return exists
+}
+
+/* Automatic linking of provider to the user with the same verified email (disabled by default) */
+func autoLinkVerifiedEmail() bool {
+	return viper.GetBool("auth.auto_link_verified_email")
+}
+
+/* Modes of sign-in through provider to the existing user with the same verified email */
+const (
+	emailLinkDenied = iota // Пользователь должен войти в аккаунт и привязать провайдер самостоятельно
+	emailLinkAdopt         // Провайдер уже привязан к пользователю по email-адресу (закрепляется идентификатор аккаунта)
+	emailLinkAuto          // Автоматическая привязка провайдера к подтверждённому аккаунту
+	emailLinkClaim         // Автоматическая привязка с закреплением неподтверждённого аккаунта за владельцем email-адреса
+)
+
+/*
+* Selection of mode of sign-in through provider to the existing user with the same verified email
+* (provider linked by email only is adopted regardless of automatic linking, otherwise the user can not sign in)
+ */
+func emailLinkMode(legacyLinked, autoLink, activated bool) int {
+	switch {
+	case legacyLinked:
+		return emailLinkAdopt
+	case !autoLink:
+		return emailLinkDenied
+	case !activated:
+		return emailLinkClaim
+	default:
+		return emailLinkAuto
+	}
+}
+
+/*
+* Claiming of the account with unconfirmed email by the owner of email, which is verified by provider
+* (password, which is set before confirmation of email, is removed and all sessions are revoked,
+* so that the account can not be registered beforehand by another person)
+ */
+func claimUnverifiedAccount(tx *sql.Tx, usersId int) error {
+	query := fmt.Sprintf("UPDATE %s SET password=NULL WHERE id=$1", tableConstants.USERS_TABLE)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE users_id = $1 AND auth_types_id IN (SELECT id FROM %s WHERE value = $2)",
+		tableConstants.USERS_AUTH_TYPES_TABLE, tableConstants.AUTH_TYPES_TABLE,
+	)
+
+	if _, err := tx.Exec(query, usersId, authConstants.AUTH_TYPE_LOCAL); err != nil {
+		return err
+	}
+
+	if _, err := revokeUserTokens(tx, usersId); err != nil {
+		return err
+	}
+
+	// Email-адрес подтверждён провайдером
+	query = fmt.Sprintf("UPDATE %s SET is_activated=true WHERE users_id=$1", tableConstants.ACTIVATIONS_TABLE)
+	_, err := tx.Exec(query, usersId)
+
+	return err
+}
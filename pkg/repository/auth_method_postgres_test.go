@@ -0,0 +1,30 @@
+package repository
+
+import "testing"
+
+/* Provider linked by email only is adopted even if automatic linking is disabled */
+func TestEmailLinkMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		legacyLinked bool
+		autoLink     bool
+		activated    bool
+		want         int
+	}{
+		{name: "legacy link without auto-link", legacyLinked: true, autoLink: false, activated: true, want: emailLinkAdopt},
+		{name: "legacy link of unconfirmed account", legacyLinked: true, autoLink: false, activated: false, want: emailLinkAdopt},
+		{name: "legacy link with auto-link", legacyLinked: true, autoLink: true, activated: false, want: emailLinkAdopt},
+		{name: "new link without auto-link", legacyLinked: false, autoLink: false, activated: true, want: emailLinkDenied},
+		{name: "new link of unconfirmed account without auto-link", legacyLinked: false, autoLink: false, activated: false, want: emailLinkDenied},
+		{name: "new link with auto-link", legacyLinked: false, autoLink: true, activated: true, want: emailLinkAuto},
+		{name: "new link of unconfirmed account with auto-link", legacyLinked: false, autoLink: true, activated: false, want: emailLinkClaim},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := emailLinkMode(tt.legacyLinked, tt.autoLink, tt.activated); got != tt.want {
+				t.Errorf("emailLinkMode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
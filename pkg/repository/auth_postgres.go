@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"context"
 	"crypto/sha1"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	config "main-server/config"
@@ -14,7 +17,6 @@ import (
 	tableConstants "main-server/pkg/constant/table"
 	"main-server/pkg/model/email"
 	rbacModel "main-server/pkg/model/rbac"
-	"main-server/pkg/model/user"
 	userModel "main-server/pkg/model/user"
 	authService "main-server/pkg/service/auth"
 	smtpService "main-server/pkg/service/smtp"
@@ -25,6 +27,7 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/jmoiron/sqlx"
 	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/oauth2"
@@ -32,14 +35,14 @@ import (
 
 type AuthPostgres struct {
 	db           *sqlx.DB
-	enforcer     *casbin.Enforcer
+	enforcer     *casbin.SyncedEnforcer
 	userPostgres UserPostgres
 }
 
 /*
 * Функция создания экземпляра сервиса
  */
-func NewAuthPostgres(db *sqlx.DB, enforcer *casbin.Enforcer, userPostgres UserPostgres) *AuthPostgres {
+func NewAuthPostgres(db *sqlx.DB, enforcer *casbin.SyncedEnforcer, userPostgres UserPostgres) *AuthPostgres {
 	return &AuthPostgres{
 		db:           db,
 		enforcer:     enforcer,
@@ -48,7 +51,7 @@ func NewAuthPostgres(db *sqlx.DB, enforcer *casbin.Enforcer, userPostgres UserPo
 }
 
 /* Функция регистрации пользователя */
-func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel) (userModel.UserAuthDataModel, error) {
+func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
 	check := CheckRowExists(r.db, tableConstants.USERS_TABLE, "email", user.Email)
 
 	if check {
@@ -105,14 +108,6 @@ func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel) (userModel.U
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 LIMIT 1", tableConstants.DOMAINS_TABLE)
-	var domain rbacModel.DomainModel
-	err = r.db.Get(&domain, query, viper.GetString("domain"))
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New("Домена не существует!")
-	}
-
 	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 AND domains_id = $2 LIMIT 1", tableConstants.ROLES_TABLE)
 	var role rbacModel.RoleModel
 	err = r.db.Get(&role, query, roleConstant.ROLE_USER, domain.Id)
@@ -140,22 +135,24 @@ func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel) (userModel.U
 		return userModel.UserAuthDataModel{}, err
 	}
 
+	// Каждый вход создаёт отдельную сессию пользователя
+	sessionUuid := uuid.NewV4().String()
+
 	// Генерация токенов доступа и обновления
-	accessToken, err := GenerateToken(userUuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	accessToken, err := GenerateAccessToken(userUuid, authTypes.Uuid, domain.Uuid, sessionUuid, nil, 0, authConstants.TOKEN_TLL_ACCESS)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	refreshToken, err := GenerateToken(userUuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
+	refreshToken, err := GenerateToken(userUuid, authTypes.Uuid, domain.Uuid, sessionUuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
 	// Добавление токенов доступа и обновления в БД
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.TOKENS_TABLE)
-	_, err = tx.Exec(query, id, accessToken, refreshToken)
+	err = createSession(tx, id, authTypes.Id, sessionUuid, accessToken, refreshToken, session)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
@@ -164,49 +161,16 @@ func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel) (userModel.U
 	// Генерация UUID
 	u2 := uuid.NewV4()
 
-	query = fmt.Sprintf("INSERT INTO %s (users_id, is_activated, activation_link) values ($1, $2, $3)", tableConstants.ACTIVATIONS_TABLE)
-	_, err = tx.Exec(query, id, false, u2)
+	// Ссылка для подтверждения аккаунта действительна ограниченное время
+	query = fmt.Sprintf("INSERT INTO %s (users_id, is_activated, activation_link, expires_at) values ($1, $2, $3, $4)", tableConstants.ACTIVATIONS_TABLE)
+	_, err = tx.Exec(query, id, false, u2, time.Now().Add(authConstants.TOKEN_TLL_ACTIVATION))
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
 	// Отправка сообщения на почту нового пользователя
-	err = smtpService.SendMessage(user.Email, smtpService.BuildMessage(email.Mail{
-		Sender:  viper.GetString("smtp.email"),
-		To:      []string{user.Email},
-		Subject: "Подтверждение аккаунта \"МИСУ Мирный\"",
-		Body: fmt.Sprintf(`<html>
-		<head>
-			<meta charset="utf-8" />
-			<title></title>
-		</head>
-		<style>
-			body {background-color: #FEFEF9;}
-			h2   {color: #181511;}
-			button {
-				color: rgb(0, 0, 0);
-				outline: none;
-				border: none;
-				border-radius: 30px;
-				background-color: #B19472;
-				padding: 8px 16px;
-				margin-top: 16px;
-				cursor: pointer;
-			}
-		</style>
-		<body>
-			<h2>Подтверждение E-mail</h2>
-			<br><text>Вы получили это письмо, так как Ваш почтовый адрес был указан в приложении "МИСУ Мирный".</text> 
-			</br><text>Чтобы подтвердить Вашу почту перейдите по ссылке: </text></br>
-			<a href="%s">
-			<button>Подтвердить E-mail</button>
-			</a>
-			<br><br><br>
-			<text>Если Вы не проходили процедуру регистрации в приложении "МИСУ Мирный", то не отвечайте на данное сообщение.</text>
-		</body>
-	</html>`, viper.GetString("api_url")+"/auth/activate/"+u2.String()),
-	}))
+	err = sendActivationMessage(user.Email, u2.String())
 
 	if err != nil {
 		tx.Rollback()
@@ -226,90 +190,191 @@ func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel) (userModel.U
 }
 
 /* Функция авторизации пользователя */
-func (r *AuthPostgres) LoginUser(user userModel.UserLoginModel) (userModel.UserAuthDataModel, error) {
+func (r *AuthPostgres) LoginUser(user userModel.UserLoginModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
+	// Проверка ограничения неудачных попыток входа (по аккаунту и по IP-адресу)
+	if err := checkAttempts(r.db, signInAttemptsPolicy, user.Email, session.Ip); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
 	// Модель пользовательских данных
 	var findUser userModel.UserModel
 
 	// Формирование запроса на получение данных из таблицы всех пользователей
 	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.email = $1 LIMIT 1", tableConstants.USERS_TABLE)
 
-	// Получение данных по SQL-запросу
-	if err := r.db.Get(&findUser, query, user.Email); err != nil {
-		return userModel.UserAuthDataModel{}, errors.New("Пользователя с данным почтовым адресом не существует!")
+	// Получение данных по SQL-запросу (отсутствие пользователя не отличается от неправильного пароля)
+	err := r.db.Get(&findUser, query, user.Email)
+	if err != nil && err != sql.ErrNoRows {
+		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Хэширование пароля пользователя с помощью пакета bcrypt
-	if err := bcrypt.CompareHashAndPassword([]byte(findUser.Password), []byte(user.Password)); err != nil {
-		return userModel.UserAuthDataModel{}, errors.New("Не правильный пароль! Повторите попытку")
+	// Вход по паролю возможен только при привязанном локальном способе входа
+	if !comparePassword(findUser.Password, user.Password) || !hasAuthType(r.db, findUser.Id, authConstants.AUTH_TYPE_LOCAL) {
+		return userModel.UserAuthDataModel{}, r.registerSignInFailure(user.Email, session.Ip)
 	}
 
-	// Начало транзакции
-	tx, err := r.db.Begin()
+	if err := resetAttempts(r.db, signInAttemptsPolicy, user.Email); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	// Проверка наличия у пользователя хотя бы одной роли в доменной области запроса
+	if !r.hasDomainRoles(findUser.Id, domain.Id) {
+		return userModel.UserAuthDataModel{}, errors.New("Данный пользователь не имеет доступа к данному домену!")
+	}
+
+	// При включённой двухфакторной аутентификации выдаётся только токен ожидания второго фактора
+	if isMfaEnabled(r.db, findUser.Id) {
+		mfaToken, err := GenerateMfaToken(findUser.Uuid, domain.Uuid, authConstants.TOKEN_TLL_MFA, viper.GetString("token.signing_key_mfa"))
+		if err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		return userModel.UserAuthDataModel{
+			MfaToken: mfaToken,
+		}, nil
+	}
+
+	return r.createLocalSession(findUser, domain, session)
+}
+
+/* Функция завершения авторизации пользователя вторым фактором (код TOTP или код восстановления) */
+func (r *AuthPostgres) LoginUserMfa(data userModel.UserMfaLoginModel, token userModel.MfaTokenOutputParse, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
+	if token.DomainUuid != domain.Uuid {
+		return userModel.UserAuthDataModel{}, errors.New("Токен двухфакторной аутентификации выдан для другого домена!")
+	}
+
+	findUser, err := r.GetUser("id", strconv.Itoa(token.UsersId))
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.users_id = $1", tableConstants.TOKENS_TABLE)
-	if _, err := r.db.Exec(query, findUser.Id); err != nil {
-		tx.Rollback()
+	// Неверные коды второго фактора ограничиваются так же, как неверные пароли
+	if err := checkAttempts(r.db, signInAttemptsPolicy, findUser.Email, session.Ip); err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Выделение доменной области, в рамках которой происходит проверка доступа
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 LIMIT 1", tableConstants.DOMAINS_TABLE)
-	var domain rbacModel.DomainModel
-	err = r.db.Get(&domain, query, viper.GetString("domain"))
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New("Домена не существует!")
+	if err := verifyMfaCode(r.db, findUser.Id, data.Code); err != nil {
+		r.registerSignInFailure(findUser.Email, session.Ip)
+		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 AND domains_id = $2 LIMIT 1", tableConstants.ROLES_TABLE)
-	var role rbacModel.RoleModel
-	err = r.db.Get(&role, query, roleConstant.ROLE_USER, domain.Id)
+	if err := resetAttempts(r.db, signInAttemptsPolicy, findUser.Email); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return r.createLocalSession(findUser, domain, session)
+}
+
+/*
+* Registration of failed attempt of sign-in
+* (on lockout of account e-mail with link for unlock is sent, if account exists)
+ */
+func (r *AuthPostgres) registerSignInFailure(userEmail, ip string) error {
+	unlockToken, err := registerFailedAttempt(r.db, signInAttemptsPolicy, userEmail, ip)
 	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New("Роли пользователя для данного домена не существует!")
+		return err
 	}
 
-	// Получение роли пользователя по его ID и доменной области
-	datas, _ := r.enforcer.GetRolesForUser(strconv.Itoa(findUser.Id), strconv.Itoa(domain.Id))
+	if unlockToken != "" {
+		if user, err := r.GetUser("email", userEmail); err == nil {
+			// Письмо отправляется в фоне, чтобы время ответа не раскрывало существование аккаунта
+			go func() {
+				if err := sendUnlockMessage(user.Email, unlockToken); err != nil {
+					logrus.Errorf("failed to send unlock message: %s", err.Error())
+				}
+			}()
+		}
+	}
+
+	return errors.New("Неверный email-адрес или пароль!")
+}
 
-	fmt.Println(datas)
+/* Отправка письма со ссылкой для разблокировки аккаунта */
+func sendUnlockMessage(userEmail, unlockToken string) error {
+	return smtpService.SendMessage(userEmail, smtpService.BuildMessage(email.Mail{
+		Sender:  viper.GetString("smtp.email"),
+		To:      []string{userEmail},
+		Subject: "Блокировка аккаунта \"МИСУ Мирный\"",
+		Body: fmt.Sprintf(`<html>
+		<head>
+			<meta charset="utf-8" />
+			<title></title>
+		</head>
+		<style>
+			body {background-color: #FEFEF9;}
+			h2   {color: #181511;}
+			button {
+				color: rgb(0, 0, 0);
+				outline: none;
+				border: none;
+				border-radius: 30px;
+				background-color: #B19472;
+				padding: 8px 16px;
+				margin-top: 16px;
+				cursor: pointer;
+			}
+		</style>
+		<body>
+			<h2>Аккаунт временно заблокирован</h2>
+			<br><text>Вход в Ваш аккаунт в приложении "МИСУ Мирный" временно заблокирован из-за большого количества неудачных попыток входа.</text> 
+			</br><text>Если это были Вы, то разблокировать аккаунт можно по ссылке: </text></br>
+			<a href="%s">
+			<button>Разблокировать аккаунт</button>
+			</a>
+			<br><br><br>
+			<text>Если Вы не пытались войти в приложение "МИСУ Мирный", то рекомендуем сменить пароль.</text>
+		</body>
+	</html>`, viper.GetString("api_url")+"/auth/unlock/"+unlockToken),
+	}))
+}
 
-	// Проверка наличия роли у пользователя
-	flag, _ := r.enforcer.HasRoleForUser(strconv.Itoa(findUser.Id), strconv.Itoa(role.Id), strconv.Itoa(domain.Id))
+/* Разблокировка аккаунта по ссылке из письма */
+func (r *AuthPostgres) UnlockAccount(link string) (bool, error) {
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.scope = $1 AND tl.unlock_token_hash = $2 RETURNING id", tableConstants.AUTH_ATTEMPTS_TABLE)
 
-	if !flag {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New("Данный пользователь не имеет доступа к данному домену!")
+	var id int
+	if err := r.db.QueryRow(query, authConstants.ATTEMPTS_SCOPE_SIGN_IN, hashToken(link)).Scan(&id); err != nil {
+		return false, errors.New("Ссылка для разблокировки аккаунта недействительна!")
+	}
+
+	return true, nil
+}
+
+/* Создание новой сессии пользователя с локальным типом авторизации */
+func (r *AuthPostgres) createLocalSession(findUser userModel.UserModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
 	}
 
 	// Получение типа авторизации (в данном случае - LOCAL)
 	var authTypes userModel.AuthTypeModel
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.AUTH_TYPES_TABLE)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.AUTH_TYPES_TABLE)
 	err = r.db.Get(&authTypes, query, authConstants.AUTH_TYPE_LOCAL)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, errors.New(err.Error())
 	}
 
+	// Каждый вход создаёт отдельную сессию пользователя
+	sessionUuid := uuid.NewV4().String()
+
 	// Генерация токенов доступа и обновления
-	accessToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	accessToken, err := GenerateAccessToken(findUser.Uuid, authTypes.Uuid, domain.Uuid, sessionUuid, nil, findUser.TokenVersion, authConstants.TOKEN_TLL_ACCESS)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	refreshToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
+	refreshToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, domain.Uuid, sessionUuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
 	// Установка токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.TOKENS_TABLE)
-	_, err = tx.Exec(query, findUser.Id, accessToken, refreshToken)
+	err = createSession(tx, findUser.Id, authTypes.Id, sessionUuid, accessToken, refreshToken, session)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
@@ -327,8 +392,47 @@ func (r *AuthPostgres) LoginUser(user userModel.UserLoginModel) (userModel.UserA
 	}, nil
 }
 
-/* Создание нового пользователя с помощью Google OAuth2 */
-func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token *oauth2.Token) (userModel.UserAuthDataModel, error) {
+/* Отправка письма со ссылкой для подтверждения аккаунта */
+func sendActivationMessage(userEmail, link string) error {
+	return smtpService.SendMessage(userEmail, smtpService.BuildMessage(email.Mail{
+		Sender:  viper.GetString("smtp.email"),
+		To:      []string{userEmail},
+		Subject: "Подтверждение аккаунта \"МИСУ Мирный\"",
+		Body: fmt.Sprintf(`<html>
+		<head>
+			<meta charset="utf-8" />
+			<title></title>
+		</head>
+		<style>
+			body {background-color: #FEFEF9;}
+			h2   {color: #181511;}
+			button {
+				color: rgb(0, 0, 0);
+				outline: none;
+				border: none;
+				border-radius: 30px;
+				background-color: #B19472;
+				padding: 8px 16px;
+				margin-top: 16px;
+				cursor: pointer;
+			}
+		</style>
+		<body>
+			<h2>Подтверждение E-mail</h2>
+			<br><text>Вы получили это письмо, так как Ваш почтовый адрес был указан в приложении "МИСУ Мирный".</text> 
+			</br><text>Чтобы подтвердить Вашу почту перейдите по ссылке: </text></br>
+			<a href="%s">
+			<button>Подтвердить E-mail</button>
+			</a>
+			<br><br><br>
+			<text>Если Вы не проходили процедуру регистрации в приложении "МИСУ Мирный", то не отвечайте на данное сообщение.</text>
+		</body>
+	</html>`, viper.GetString("api_url")+"/auth/activate/"+link),
+	}))
+}
+
+/* Функция регистрации пользователя через внешний провайдер авторизации */
+func (r *AuthPostgres) CreateUserProvider(user userModel.ProviderUserModel, provider string, token *oauth2.Token, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
 	check := CheckRowExists(r.db, tableConstants.USERS_TABLE, "email", user.Email)
 
 	if check {
@@ -344,13 +448,10 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 	var id int
 	var userUuid string
 
-	// Запрос на добавление нового пользователя в систему
-	query := fmt.Sprintf("INSERT INTO %s (email, password, uuid) values ($1, $2, $3) RETURNING id, uuid", tableConstants.USERS_TABLE)
-
-	// Генерация UUID
-	u1 := uuid.NewV4()
+	// Запрос на добавление нового пользователя в систему (пароль отсутствует до его установки пользователем)
+	query := fmt.Sprintf("INSERT INTO %s (email, uuid) values ($1, $2) RETURNING id, uuid", tableConstants.USERS_TABLE)
 
-	row := tx.QueryRow(query, user.Email, token.AccessToken, u1)
+	row := tx.QueryRow(query, user.Email, uuid.NewV4())
 	if err := row.Scan(&id, &userUuid); err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, errors.New("Пользователь с данными регистрационными данными уже существует!")
@@ -365,24 +466,19 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 		tableConstants.USERS_DATA_TABLE)
 
 	userJsonb, err := json.Marshal(userModel.UserJSONBModel{
-		Name:     user.Name,
+		Name:     user.GivenName,
 		Surname:  user.FamilyName,
-		Nickname: user.GivenName,
+		Nickname: user.Name,
 	})
-
-	_, err = tx.Exec(query, userJsonb, currentDate, currentDate, id)
-
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 LIMIT 1", tableConstants.DOMAINS_TABLE)
-	var domain rbacModel.DomainModel
-	err = r.db.Get(&domain, query, viper.GetString("domain"))
+	_, err = tx.Exec(query, userJsonb, currentDate, currentDate, id)
 	if err != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New("Домена не существует!")
+		return userModel.UserAuthDataModel{}, err
 	}
 
 	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 AND domains_id = $2 LIMIT 1", tableConstants.ROLES_TABLE)
@@ -393,95 +489,103 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 		return userModel.UserAuthDataModel{}, errors.New("Роли пользователя не существует!")
 	}
 
-	// Добавление роли пользователя по умолчанию
-	r.enforcer.AddRoleForUserInDomain(strconv.Itoa(id), strconv.Itoa(role.Id), strconv.Itoa(domain.Id))
-
-	// Установка типа аутентификации пользователя (в данном случае - GOOGLE)
-	var authTypes userModel.AuthTypeModel
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.AUTH_TYPES_TABLE)
-	err = r.db.Get(&authTypes, query, authConstants.AUTH_TYPE_GOOGLE)
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New(err.Error())
-	}
-
-	query = fmt.Sprintf("INSERT INTO %s (users_id, auth_types_id) values ($1, $2)", tableConstants.USERS_AUTH_TYPES_TABLE)
-	_, err = tx.Exec(query, id, authTypes.Id)
+	// Установка типа аутентификации пользователя (имя провайдера)
+	authTypes, err := linkIdentity(tx, id, provider, user, token)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация токенов доступа
-	accessToken, err := GenerateToken(userUuid, authTypes.Uuid, &token.AccessToken, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	// Email-адрес подтверждён провайдером, поэтому аккаунт сразу активирован
+	query = fmt.Sprintf("INSERT INTO %s (users_id, is_activated, activation_link) values ($1, $2, $3)", tableConstants.ACTIVATIONS_TABLE)
+	_, err = tx.Exec(query, id, true, uuid.NewV4())
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация токена обновления
-	refreshToken, err := GenerateToken(userUuid, authTypes.Uuid, &token.RefreshToken, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_access"))
+	data, err := createExternalSession(tx, id, userUuid, 0, authTypes, domain, session)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Установка токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.TOKENS_TABLE)
-	_, err = tx.Exec(query, id, accessToken, refreshToken)
+	err = tx.Commit()
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация UUID
-	u2 := uuid.NewV4()
-	query = fmt.Sprintf("INSERT INTO %s (users_id, is_activated, activation_link) values ($1, $2, $3)", tableConstants.ACTIVATIONS_TABLE)
-	_, err = tx.Exec(query, id, true, u2)
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
-	}
+	// Добавление роли пользователя по умолчанию (после фиксации транзакции)
+	r.enforcer.AddRoleForUserInDomain(strconv.Itoa(id), strconv.Itoa(role.Id), strconv.Itoa(domain.Id))
 
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, tx.Commit()
+	return data, nil
 }
 
-/* Функция авторизации пользователя через Google OAuth2 */
-func (r *AuthPostgres) LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error) {
-	// Получение токена доступа на основе сгенерированного кода
-	token, err := config.AppOAuth2Config.GoogleLogin.Exchange(oauth2.NoContext, code)
+/* Функция авторизации пользователя через внешний провайдер авторизации (Google, VK, OpenID Connect) */
+func (r *AuthPostgres) LoginUserProvider(provider, code string, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
+	authProvider, ok := authService.GetProvider(provider)
+	if !ok {
+		return userModel.UserAuthDataModel{}, errors.New("Данный способ авторизации не поддерживается!")
+	}
+
+	ctx := context.Background()
 
+	// Получение токена доступа на основе сгенерированного кода
+	token, err := authProvider.Exchange(ctx, code)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Проверка токена доступа (его верификация)
-	is_verify, err := authService.VerifyAccessToken(token.AccessToken)
-
+	userData, err := authProvider.UserInfo(ctx, token)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	if !is_verify {
-		return userModel.UserAuthDataModel{}, errors.New("Данный токен не принадлежит данному пользователю!")
-	}
+	// Поиск пользователя, к которому уже привязан данный аккаунт провайдера
+	claimAccount := false
+	findUser, err := getUserByIdentity(r.db, provider, userData.Subject)
+	if err != nil {
+		if userData.Email == "" {
+			return userModel.UserAuthDataModel{}, errors.New("Провайдер не предоставил email-адрес пользователя!")
+		}
 
-	var findUser userModel.UserModel
-	var userData userModel.UserRegisterOAuth2Model
+		if !userData.EmailVerified {
+			return userModel.UserAuthDataModel{}, errors.New("Email-адрес пользователя не подтверждён провайдером!")
+		}
+
+		query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.email = $1 LIMIT 1", tableConstants.USERS_TABLE)
+		if err := r.db.Get(&findUser, query, userData.Email); err != nil {
+			// Если пользователя не существует - создаём его (если регистрация в домене доступна)
+			if !config.IsSignUpDomain(domain.Value) {
+				return userModel.UserAuthDataModel{}, errors.New("Регистрация в данном домене недоступна!")
+			}
 
-	userData, err = authService.GetUserInfo(token)
+			return r.CreateUserProvider(userData, provider, token, domain, session)
+		}
 
-	if err != nil {
-		return userModel.UserAuthDataModel{}, err
+		legacyLinked := hasLegacyAuthType(r.db, findUser.Id, provider)
+		autoLink := autoLinkVerifiedEmail()
+
+		// Аккаунт с неподтверждённым email-адресом мог быть зарегистрирован не владельцем email-адреса
+		activated := true
+		if !legacyLinked && autoLink {
+			if activated, err = r.IsActivated(findUser.Id); err != nil {
+				return userModel.UserAuthDataModel{}, err
+			}
+		}
+
+		switch emailLinkMode(legacyLinked, autoLink, activated) {
+		case emailLinkDenied:
+			return userModel.UserAuthDataModel{}, errors.New("Пользователь с данным email-адресом уже существует! Войдите в аккаунт и привяжите данный способ входа")
+		case emailLinkClaim:
+			claimAccount = true
+		}
 	}
 
-	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.email = $1 LIMIT 1", tableConstants.USERS_TABLE)
-	if err := r.db.Get(&findUser, query, userData.Email); err != nil {
-		// Если пользователя не существует - создаём его
-		return r.CreateUserOAuth2(userData, token)
+	// Проверка наличия у пользователя хотя бы одной роли в доменной области запроса
+	if !r.hasDomainRoles(findUser.Id, domain.Id) {
+		return userModel.UserAuthDataModel{}, errors.New("Данный пользователь не имеет доступа к данному домену!")
 	}
 
 	tx, err := r.db.Begin()
@@ -489,54 +593,77 @@ func (r *AuthPostgres) LoginUserOAuth2(code string) (userModel.UserAuthDataModel
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Запрос на обновление пароля в базе данных для пользователя
-	query = fmt.Sprintf("UPDATE %s SET password=$1 WHERE email=$2", tableConstants.USERS_TABLE)
+	if claimAccount {
+		if err := claimUnverifiedAccount(tx, findUser.Id); err != nil {
+			tx.Rollback()
+			return userModel.UserAuthDataModel{}, err
+		}
 
-	if _, err := r.db.Exec(query, token.AccessToken, userData.Email); err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
+		findUser.TokenVersion++
 	}
 
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.users_id = $1", tableConstants.TOKENS_TABLE)
-	if _, err := r.db.Exec(query, findUser.Id); err != nil {
+	// Привязка аккаунта провайдера (или обновление его данных и токенов)
+	authTypes, err := linkIdentity(tx, findUser.Id, provider, userData, token)
+	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Получение типа аутентификации (в данном случае - GOOGLE)
-	var authTypes userModel.AuthTypeModel
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.AUTH_TYPES_TABLE)
-	err = r.db.Get(&authTypes, query, authConstants.AUTH_TYPE_GOOGLE)
+	data, err := createExternalSession(tx, findUser.Id, findUser.Uuid, findUser.TokenVersion, authTypes, domain, session)
 	if err != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New(err.Error())
+		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация токена доступа
-	accessToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, &token.AccessToken, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	err = tx.Commit()
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация токена обновления
-	refreshToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, &token.RefreshToken, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_access"))
+	return data, nil
+}
+
+/*
+* Creating authentication types for registered providers (if they do not exist yet)
+ */
+func EnsureAuthTypes(db *sqlx.DB, values []string) error {
+	query := fmt.Sprintf(`INSERT INTO %s (uuid, value) SELECT $1, $2
+		WHERE NOT EXISTS (SELECT 1 FROM %s WHERE value = $2)`,
+		tableConstants.AUTH_TYPES_TABLE, tableConstants.AUTH_TYPES_TABLE,
+	)
+
+	for _, value := range values {
+		if _, err := db.Exec(query, uuid.NewV4().String(), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+* Creating session of user, which is authorized by external provider
+* (tokens of provider are kept only in external identities of user)
+ */
+func createExternalSession(tx *sql.Tx, usersId int, usersUuid string, tokenVersion int, authTypes userModel.AuthTypeModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
+	// Каждый вход создаёт отдельную сессию пользователя
+	sessionUuid := uuid.NewV4().String()
+
+	// Генерация токена доступа
+	accessToken, err := GenerateAccessToken(usersUuid, authTypes.Uuid, domain.Uuid, sessionUuid, nil, tokenVersion, authConstants.TOKEN_TLL_ACCESS)
 	if err != nil {
-		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Установка токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.TOKENS_TABLE)
-	_, err = tx.Exec(query, findUser.Id, accessToken, refreshToken)
+	// Генерация токена обновления
+	refreshToken, err := GenerateToken(usersUuid, authTypes.Uuid, domain.Uuid, sessionUuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
 	if err != nil {
-		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		tx.Rollback()
+	// Установка токенов пользователю
+	if err := createSession(tx, usersId, authTypes.Id, sessionUuid, accessToken, refreshToken, session); err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
@@ -548,86 +675,98 @@ func (r *AuthPostgres) LoginUserOAuth2(code string) (userModel.UserAuthDataModel
 
 /*
 * Функция обновления токена доступа
+* (каждое обновление выдаёт новый токен обновления, а предыдущий становится использованным)
  */
-func (r *AuthPostgres) Refresh(data userModel.TokenLogoutDataModel, rToken string, token userModel.TokenOutputParse) (userModel.UserAuthDataModel, error) {
+func (r *AuthPostgres) Refresh(data userModel.TokenLogoutDataModel, rToken string, token userModel.TokenOutputParse, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
 	user, err := r.userPostgres.GetUser("id", token.UsersId)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
 	var findToken userModel.TokenModel
-	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.refresh_token = $1 AND tl.users_id = $2 LIMIT 1", tableConstants.TOKENS_TABLE)
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.uuid = $1 AND tl.users_id = $2 LIMIT 1", tableConstants.TOKENS_TABLE)
 
-	if err := r.db.Get(&findToken, query, rToken, user.Id); err != nil {
+	if err := r.db.Get(&findToken, query, token.SessionUuid, user.Id); err != nil {
 		return userModel.UserAuthDataModel{}, errors.New("Пользователя с данным токеном обновления не существует!")
 	}
 
-	isValid := ValidToken(rToken, viper.GetString("token.signing_key_refresh"))
-
-	setValues := make([]string, 0)
-	args := make([]interface{}, 0)
-	argId := 1
-
-	var refreshToken string
-
-	if !isValid {
-		switch token.AuthType.Value {
-		case "LOCAL":
-			refreshToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
-			break
-
-		case "GOOGLE":
-			// Если токен от Google OAuth2 не валиден, то нужно чтобы пользователь перезашёл в приложение заново
-			//google_oauth2.RevokeToken(*token.TokenApi)
-			//r.Logout(data)
-			refreshToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, token.TokenApi, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
-			break
-		}
+	if findToken.RefreshToken != rToken {
+		return userModel.UserAuthDataModel{}, r.checkRefreshTokenReuse(findToken, rToken, session)
+	}
 
-		if err != nil {
+	// Обновление токенов внешнего провайдера авторизации (у локальных пользователей отсутствуют)
+	if token.AuthType.Value != authConstants.AUTH_TYPE_LOCAL {
+		if err := refreshIdentity(r.db, user.Id, token.AuthType.Value); err != nil {
 			return userModel.UserAuthDataModel{}, err
 		}
+	}
 
-		setValues = append(setValues, fmt.Sprintf("refresh_token=$%d", argId))
-		args = append(args, refreshToken)
-		argId++
-	} else {
-		refreshToken = rToken
+	refreshToken, err := GenerateToken(user.Uuid, token.AuthType.Uuid, token.DomainUuid, token.SessionUuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
 	}
 
-	var accessToken string
+	accessToken, err := GenerateAccessToken(user.Uuid, token.AuthType.Uuid, token.DomainUuid, token.SessionUuid, nil, user.TokenVersion, authConstants.TOKEN_TLL_ACCESS)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	switch token.AuthType.Value {
-	case authConstants.AUTH_TYPE_LOCAL:
-		accessToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, nil, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
-		break
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	case authConstants.AUTH_TYPE_GOOGLE:
-		tokenData, err := authService.RefreshAccessToken(oauth2.NoContext, *token.TokenApi)
-		accessToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, &tokenData.AccessToken, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	// Замена токенов сессии (только если токен обновления не был заменён параллельным запросом)
+	query = fmt.Sprintf(`UPDATE %s tl SET refresh_token=$1, access_token=$2, user_agent=$3, ip=$4, last_seen_at=$5
+		WHERE tl.id = $6 AND tl.refresh_token = $7`,
+		tableConstants.TOKENS_TABLE,
+	)
 
-		if err != nil {
-			return userModel.UserAuthDataModel{}, err
-		}
+	result, err := tx.Exec(query, refreshToken, accessToken, nullString(session.UserAgent), nullString(session.Ip), time.Now(), findToken.Id, rToken)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
 	}
 
+	count, err := result.RowsAffected()
 	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if count == 0 {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, r.checkRefreshTokenReuse(findToken, rToken, session)
+	}
+
+	// Отзыв заменённого токена доступа (иначе после выхода он действовал бы до истечения срока)
+	if err := revokeAccessToken(tx, accessTokenId(findToken.AccessToken)); err != nil {
+		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	setValues = append(setValues, fmt.Sprintf("access_token=$%d", argId))
-	args = append(args, accessToken)
-	argId++
+	currentDate := time.Now()
 
-	setQuery := strings.Join(setValues, ", ")
+	// Удаление использованных токенов, срок действия которых уже истёк
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.used_at <= $1", tableConstants.TOKENS_USED_TABLE)
+	_, err = tx.Exec(query, currentDate.Add(-authConstants.TOKEN_TLL_REFRESH))
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	query = fmt.Sprintf("UPDATE %s tl SET %s WHERE tl.users_id = $%d",
-		tableConstants.TOKENS_TABLE, setQuery, argId)
-	args = append(args, user.Id)
+	// Сохранение использованного токена обновления для обнаружения его повторного предъявления
+	query = fmt.Sprintf("INSERT INTO %s (tokens_id, token_hash, used_at) values ($1, $2, $3)", tableConstants.TOKENS_USED_TABLE)
+	_, err = tx.Exec(query, findToken.Id, hashToken(rToken), currentDate)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	// Обновление данных о токене пользователя
-	_, err = r.db.Exec(query, args...)
+	err = tx.Commit()
 	if err != nil {
+		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
@@ -638,12 +777,50 @@ func (r *AuthPostgres) Refresh(data userModel.TokenLogoutDataModel, rToken strin
 	}, nil
 }
 
+/*
+* Проверка повторного предъявления использованного токена обновления
+* (при повторном использовании отзывается вся сессия, так как токен мог быть украден)
+ */
+func (r *AuthPostgres) checkRefreshTokenReuse(findToken userModel.TokenModel, rToken string, session userModel.SessionDataModel) error {
+	query := fmt.Sprintf("SELECT count(*) FROM %s tl WHERE tl.tokens_id = $1 AND tl.token_hash = $2", tableConstants.TOKENS_USED_TABLE)
+
+	var count int
+	if err := r.db.Get(&count, query, findToken.Id, hashToken(rToken)); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return errors.New("Пользователя с данным токеном обновления не существует!")
+	}
+
+	// Использованные токены сессии удаляются каскадно, токен доступа сессии отзывается
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := deleteSessions(tx, "tl.id = $1", findToken.Id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	logrus.Warnf("possible refresh token theft: reuse of refresh token of session %s (users_id=%d, ip=%s, user_agent=%s), session is revoked",
+		findToken.Uuid, findToken.UsersId, session.Ip, session.UserAgent)
+
+	return errors.New("Токен обновления уже был использован! Сессия завершена, выполните вход повторно")
+}
+
 /*
 *	Функция подтверждения аккаунта
  */
 func (r *AuthPostgres) Activate(link string) (bool, error) {
 	var findActivate userModel.UserActivateModel
-	query := fmt.Sprintf("SELECT activation_link, is_activated FROM %s WHERE activation_link = $1", tableConstants.ACTIVATIONS_TABLE)
+	query := fmt.Sprintf("SELECT activation_link, is_activated, expires_at FROM %s WHERE activation_link = $1", tableConstants.ACTIVATIONS_TABLE)
 
 	if err := r.db.Get(&findActivate, query, link); err != nil {
 		return false, errors.New(err.Error())
@@ -653,6 +830,10 @@ func (r *AuthPostgres) Activate(link string) (bool, error) {
 		return true, nil
 	}
 
+	if findActivate.ExpiresAt != nil && time.Now().After(*findActivate.ExpiresAt) {
+		return false, errors.New("Срок действия ссылки для подтверждения аккаунта истёк! Запросите новую ссылку")
+	}
+
 	query = fmt.Sprintf("UPDATE %s SET is_activated=%s WHERE activation_link = $1", tableConstants.ACTIVATIONS_TABLE, "true")
 
 	_, err := r.db.Exec(query, link)
@@ -665,56 +846,126 @@ func (r *AuthPostgres) Activate(link string) (bool, error) {
 }
 
 /*
-* Функция разлогирования пользователя
+* Повторная отправка ссылки для подтверждения аккаунта
+* (предыдущая ссылка становится недействительной, ответ не раскрывает существование аккаунта)
  */
-func (r *AuthPostgres) Logout(data userModel.TokenLogoutDataModel) (bool, error) {
-	// Выход из аккаунта зависит от метода аутентификации (предварительная проверка обязательна)
-	switch data.AuthTypeValue {
-	case authConstants.AUTH_TYPE_GOOGLE:
-		authService.RevokeToken(*data.TokenApi)
-		break
+func (r *AuthPostgres) ResendActivation(userEmail string, session userModel.SessionDataModel) (bool, error) {
+	if err := checkAttempts(r.db, activateAttemptsPolicy, userEmail, session.Ip); err != nil {
+		return false, err
+	}
+
+	if _, err := registerFailedAttempt(r.db, activateAttemptsPolicy, userEmail, session.Ip); err != nil {
+		return false, err
+	}
+
+	user, err := r.GetUser("email", userEmail)
+	if err != nil {
+		return true, nil
+	}
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
 	}
 
-	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.access_token=$1 AND tl.refresh_token=$2 RETURNING id", tableConstants.TOKENS_TABLE)
-	row := r.db.QueryRow(query, data.AccessToken, data.RefreshToken)
+	u2 := uuid.NewV4()
+
+	query := fmt.Sprintf("UPDATE %s SET activation_link=$1, expires_at=$2 WHERE users_id=$3 AND is_activated=false RETURNING id", tableConstants.ACTIVATIONS_TABLE)
+	row := tx.QueryRow(query, u2, time.Now().Add(authConstants.TOKEN_TLL_ACTIVATION), user.Id)
 
+	// Аккаунт уже подтверждён
 	var id int
 	if err := row.Scan(&id); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	err = sendActivationMessage(user.Email, u2.String())
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
 		return false, err
 	}
 
 	return true, nil
 }
 
-type AuthTypeValue struct {
-	Value string `json:"value" db:"value"`
+/* Проверка подтверждения аккаунта (аккаунты без записи о подтверждении считаются подтверждёнными) */
+func (r *AuthPostgres) IsActivated(usersId int) (bool, error) {
+	var isActivated bool
+	query := fmt.Sprintf("SELECT NOT EXISTS (SELECT 1 FROM %s tl WHERE tl.users_id = $1 AND tl.is_activated = false)", tableConstants.ACTIVATIONS_TABLE)
+
+	err := r.db.Get(&isActivated, query, usersId)
+
+	return isActivated, err
 }
 
-/* Функция обработки запроса на восстановление пароля */
-func (r *AuthPostgres) RecoveryPassword(userEmail string) (bool, error) {
-	// Проверка существования данного пользователя по текущему email-адресу
-	user, err := r.GetUser("email", userEmail)
+/*
+* Функция разлогирования пользователя
+* (сессия удаляется, а токен доступа отзывается до истечения срока его действия)
+ */
+func (r *AuthPostgres) Logout(data userModel.TokenLogoutDataModel) (bool, error) {
+	// Начало транзакции
+	tx, err := r.db.Begin()
 	if err != nil {
-		return false, errors.New("Пользователя с данным email-адресом не существует!")
+		return false, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.uuid=$1 AND tl.access_token=$2 AND tl.refresh_token=$3 RETURNING id", tableConstants.TOKENS_TABLE)
+	row := tx.QueryRow(query, data.SessionUuid, data.AccessToken, data.RefreshToken)
+
+	var id int
+	if err := row.Scan(&id); err != nil {
+		tx.Rollback()
+		return false, err
 	}
 
-	query := fmt.Sprintf(`SELECT value FROM %s tl
-	INNER JOIN %s td on td.auth_types_id = tl.id WHERE td.users_id=$1 LIMIT 1`,
-		tableConstants.AUTH_TYPES_TABLE, tableConstants.USERS_AUTH_TYPES_TABLE)
+	if err := revokeAccessToken(tx, data.TokenId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
 
-	var authType AuthTypeValue
-	err = r.db.Get(&authType, query, user.Id)
+	err = tx.Commit()
 	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, nil
+}
+
+/*
+* Функция обработки запроса на восстановление пароля
+* (каждый запрос учитывается как попытка, а ответ не раскрывает существование аккаунта)
+ */
+func (r *AuthPostgres) RecoveryPassword(userEmail string, session userModel.SessionDataModel) (bool, error) {
+	if err := checkAttempts(r.db, recoveryAttemptsPolicy, userEmail, session.Ip); err != nil {
+		return false, err
+	}
+
+	if _, err := registerFailedAttempt(r.db, recoveryAttemptsPolicy, userEmail, session.Ip); err != nil {
 		return false, err
 	}
 
-	// Восстановление пароля доступно только при локальной авторизации / регистрации (её тип нельзя менять в будущем)
-	if authType.Value != authConstants.AUTH_TYPE_LOCAL {
-		return false, errors.New(`
-		Восстановление пароля для данного пользователя не поддерживается, так как 
-		пользователь авторизовался через сторонний сервис (Google, VK).
-		Пожалуйста, воспользуйтесь сторонним сервисом для авторизации пользователя
-		`)
+	// Проверка существования данного пользователя по текущему email-адресу
+	user, err := r.GetUser("email", userEmail)
+	if err != nil {
+		return true, nil
+	}
+
+	// Восстановление пароля доступно только при привязанном локальном способе входа
+	if !hasAuthType(r.db, user.Id, authConstants.AUTH_TYPE_LOCAL) {
+		return true, nil
 	}
 
 	// Начало транзакции
@@ -723,7 +974,7 @@ func (r *AuthPostgres) RecoveryPassword(userEmail string) (bool, error) {
 		return false, err
 	}
 
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE users_id=$1", tableConstants.RESET_TOKENS_TABLE)
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE users_id=$1", tableConstants.RESET_TOKENS_TABLE)
 
 	_, err = tx.Exec(query, user.Id)
 	if err != nil {
@@ -834,6 +1085,13 @@ func (r *AuthPostgres) ResetPassword(data userModel.ResetPasswordModel, token us
 		return false, err
 	}
 
+	// Завершение всех сессий пользователя (выданные ранее токены доступа становятся недействительными)
+	_, err = revokeUserTokens(tx, token.UsersId)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
 	err = tx.Commit()
 
 	if err != nil {
@@ -844,6 +1102,45 @@ func (r *AuthPostgres) ResetPassword(data userModel.ResetPasswordModel, token us
 	return true, nil
 }
 
+/*
+* Creating a new session of user (one row of tokens table per device)
+ */
+func createSession(tx *sql.Tx, usersId, authTypesId int, sessionUuid, accessToken, refreshToken string, session userModel.SessionDataModel) error {
+	query := fmt.Sprintf(`INSERT INTO %s (users_id, auth_types_id, access_token, refresh_token, uuid, device, user_agent, ip, created_at, last_seen_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`, tableConstants.TOKENS_TABLE)
+
+	currentDate := time.Now()
+	_, err := tx.Exec(query, usersId, authTypesId, accessToken, refreshToken, sessionUuid,
+		nullString(session.Device), nullString(session.UserAgent), nullString(session.Ip), currentDate, currentDate)
+
+	return err
+}
+
+/* Only hash of used refresh token is stored */
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
+
+/* Empty strings are stored as NULL */
+func nullString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
+
+/*
+* Checking that the user has at least one role in the domain
+ */
+func (r *AuthPostgres) hasDomainRoles(usersId, domainsId int) bool {
+	roles, err := r.enforcer.GetRolesForUser(strconv.Itoa(usersId), strconv.Itoa(domainsId))
+
+	return err == nil && len(roles) > 0
+}
+
 /*
 * User data acquisition function
  */
@@ -894,26 +1191,55 @@ func generatePasswordHash(password string) string {
 /* Token Body Structure */
 type tokenClaims struct {
 	jwt.StandardClaims
-	UsersId     string  `json:"users_id"`      // ID пользователя
-	AuthTypesId string  `json:"auth_types_id"` // Тип аутентификации пользователя
-	TokenApi    *string `json:"token_api"`     // Внешний токен доступа
+	UsersId      string  `json:"users_id"`      // ID пользователя
+	AuthTypesId  string  `json:"auth_types_id"` // Тип аутентификации пользователя
+	TokenApi     *string `json:"token_api"`     // Внешний токен доступа
+	DomainsId    string  `json:"domains_id"`    // Домен, для которого выдан токен
+	SessionsId   string  `json:"sessions_id"`   // Сессия (устройство) пользователя
+	TokenVersion int     `json:"token_version"` // Версия токенов доступа пользователя
 }
 
-/*
-* Token generation function
- */
-func GenerateToken(uuid, authTypesUuid string, tokenApi *string, tokenTTL time.Duration, signingKey string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+func newTokenClaims(usersUuid, authTypesUuid, domainUuid, sessionUuid string, tokenApi *string, tokenTTL time.Duration) *tokenClaims {
+	return &tokenClaims{
 		jwt.StandardClaims{
+			// Уникальный идентификатор токена (токены, выданные в одну секунду, не совпадают)
+			Id:        uuid.NewV4().String(),
 			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
-		uuid,
+		usersUuid,
 		authTypesUuid,
 		tokenApi,
-	})
+		domainUuid,
+		sessionUuid,
+		0,
+	}
+}
+
+/*
+* Token generation function (refresh token, which is verified only by this server)
+ */
+func GenerateToken(usersUuid, authTypesUuid, domainUuid, sessionUuid string, tokenApi *string, tokenTTL time.Duration, signingKey string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newTokenClaims(usersUuid, authTypesUuid, domainUuid, sessionUuid, tokenApi, tokenTTL))
 
-	return token.SignedString([]byte(signingKey))
+	key, err := authService.HmacKey(signingKey)
+	if err != nil {
+		return "", err
+	}
+
+	return token.SignedString(key)
+}
+
+/*
+* Access token generation function
+* (token is signed by asymmetric key of keyring, so other services verify it by public key from JWKS;
+* token is valid only while version of access tokens of user is not changed)
+ */
+func GenerateAccessToken(usersUuid, authTypesUuid, domainUuid, sessionUuid string, tokenApi *string, tokenVersion int, tokenTTL time.Duration) (string, error) {
+	claims := newTokenClaims(usersUuid, authTypesUuid, domainUuid, sessionUuid, tokenApi, tokenTTL)
+	claims.TokenVersion = tokenVersion
+
+	return authService.SignToken(claims)
 }
 
 /*
@@ -956,7 +1282,12 @@ func GenerateResetToken(uuid, email string, tokenTTL time.Duration, signingKey s
 		email,
 	})
 
-	return token.SignedString([]byte(signingKey))
+	key, err := authService.HmacKey(signingKey)
+	if err != nil {
+		return "", err
+	}
+
+	return token.SignedString(key)
 }
 
 /*
@@ -977,3 +1308,32 @@ func ValidResetToken(pToken, signingKey string) bool {
 
 	return true
 }
+
+/* Working with tokens of pending second factor */
+/* Token Body Structure */
+type tokenMfaClaims struct {
+	jwt.StandardClaims
+	UsersId   string `json:"users_id"`   // ID пользователя
+	DomainsId string `json:"domains_id"` // Домен, в котором выполняется вход
+}
+
+/*
+* Generation function of token of pending second factor
+ */
+func GenerateMfaToken(usersUuid, domainUuid string, tokenTTL time.Duration, signingKey string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenMfaClaims{
+		jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+		usersUuid,
+		domainUuid,
+	})
+
+	key, err := authService.HmacKey(signingKey)
+	if err != nil {
+		return "", err
+	}
+
+	return token.SignedString(key)
+}
@@ -20,19 +20,26 @@ func NewGuestPostgres(db *sqlx.DB) *GuestPostgres {
 }
 
 /*
-* Функция получения данных о роли
+* Функция получения страницы опубликованных статей
  */
-func (r *GuestPostgres) GetArticles() (articleModel.ArticlesModel, error) {
-	query := fmt.Sprintf("SELECT * FROM %s", tableConstants.ARTICLES_TABLE)
+func (r *GuestPostgres) GetArticles(domainsId int, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error) {
+	return getArticlesPage(r.db, []string{domainArticleCondition, publishedArticleCondition}, []interface{}{domainsId}, filter)
+}
 
-	var articlesDb []articleModel.ArticleDBModel
-	err := r.db.Select(&articlesDb, query)
+/*
+* Функция получения опубликованной статьи
+ */
+func (r *GuestPostgres) GetArticle(domainsId int, uuid articleModel.ArticleUuidModel) (articleModel.ArticleModel, error) {
+	var article articleModel.ArticleAuthorDBModel
 
+	query := fmt.Sprintf("%s WHERE %s AND %s AND a1.uuid = $2 LIMIT 1", articlesAuthorQuery, domainArticleCondition, publishedArticleCondition)
+
+	err := r.db.Get(&article, query, domainsId, uuid.Uuid)
 	if err != nil {
-		return articleModel.ArticlesModel{}, err
+		return articleModel.ArticleModel{}, err
 	}
 
-	var articles articleModel.ArticlesModel
+	var articlesFiles []articleModel.ArticlesFilesDBModel
 
 	query = fmt.Sprintf(`SELECT index, filename, filepath FROM %s JOIN %s ON %s.files_id = %s.id WHERE %s.articles_id=$1;`,
 		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
@@ -40,25 +47,46 @@ func (r *GuestPostgres) GetArticles() (articleModel.ArticlesModel, error) {
 		tableConstants.ARTICLES_FILES_TABLE,
 	)
 
-	for _, element := range articlesDb {
-		var files []articleModel.ArticlesFilesDBModel
-		err := r.db.Select(&files, query, element.Id)
-
-		if err != nil {
-			return articleModel.ArticlesModel{}, err
-		}
-
-		articles.Articles = append(articles.Articles, articleModel.ArticleModel{
-			Uuid:      element.Uuid,
-			Filepath:  element.Filepath,
-			Title:     element.Title,
-			Text:      element.Text,
-			Tags:      element.Tags,
-			Files:     files,
-			CreatedAt: element.CreatedAt,
-			UpdatedAt: element.UpdatedAt,
-		})
+	err = r.db.Select(&articlesFiles, query, article.Id)
+	if err != nil {
+		return articleModel.ArticleModel{}, err
 	}
 
-	return articles, nil
+	return articleModel.ArticleModel{
+		Uuid:      article.Uuid,
+		Filepath:  article.Filepath,
+		Title:     article.Title,
+		Text:      article.Text,
+		Tags:      article.Tags,
+		Files:     articlesFiles,
+		Status:    article.Status,
+		PublishAt: article.PublishAt,
+		CreatedAt: article.CreatedAt,
+		UpdatedAt: article.UpdatedAt,
+		Author:    article.Author,
+	}, nil
+}
+
+/*
+* Функция полнотекстового поиска по опубликованным статьям
+ */
+func (r *GuestPostgres) SearchArticles(domainsId int, search articleModel.ArticlesSearchRequestModel) (articleModel.ArticlesSearchModel, error) {
+	return searchArticles(r.db, []string{domainArticleCondition, publishedArticleCondition}, []interface{}{domainsId}, search)
+}
+
+/*
+* Функция получения облака тегов опубликованных статей
+ */
+func (r *GuestPostgres) GetTags(domainsId int) (articleModel.TagsModel, error) {
+	return getTagsCloud(r.db, []string{domainArticleCondition, publishedArticleCondition}, []interface{}{domainsId})
+}
+
+/*
+* Функция получения страницы опубликованных статей по тегу
+ */
+func (r *GuestPostgres) GetArticlesByTag(domainsId int, data articleModel.ArticlesByTagRequestModel) (articleModel.ArticlesModel, error) {
+	filter := data.ArticlesFilterModel
+	filter.Tags = append(filter.Tags, data.Tag)
+
+	return getArticlesPage(r.db, []string{domainArticleCondition, publishedArticleCondition}, []interface{}{domainsId}, filter)
 }
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	authService "main-server/pkg/service/auth"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type MfaPostgres struct {
+	db *sqlx.DB
+}
+
+/*
+* Функция создания экземпляра сервиса
+ */
+func NewMfaPostgres(db *sqlx.DB) *MfaPostgres {
+	return &MfaPostgres{
+		db: db,
+	}
+}
+
+/* Начало подключения двухфакторной аутентификации (генерация секрета TOTP) */
+func (r *MfaPostgres) EnrollMfa(usersId int) (userModel.MfaEnrollModel, error) {
+	var user userModel.UserModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id=$1 LIMIT 1", tableConstants.USERS_TABLE)
+
+	if err := r.db.Get(&user, query, usersId); err != nil {
+		return userModel.MfaEnrollModel{}, err
+	}
+
+	// Двухфакторная аутентификация доступна только при локальной авторизации
+	if !hasAuthType(r.db, usersId, authConstants.AUTH_TYPE_LOCAL) {
+		return userModel.MfaEnrollModel{}, errors.New("Двухфакторная аутентификация доступна только для локальных аккаунтов!")
+	}
+
+	if isMfaEnabled(r.db, usersId) {
+		return userModel.MfaEnrollModel{}, errors.New("Двухфакторная аутентификация уже включена!")
+	}
+
+	secret, uri, image, err := authService.GenerateTotpKey(user.Email)
+	if err != nil {
+		return userModel.MfaEnrollModel{}, err
+	}
+
+	// Секрет хранится в зашифрованном виде (без ключа шифрования подключение недоступно)
+	encryptedSecret, err := authService.EncryptToken(secret)
+	if err != nil {
+		if err == authService.ErrTokenCipherDisabled {
+			return userModel.MfaEnrollModel{}, errors.New("Двухфакторная аутентификация не настроена на сервере!")
+		}
+
+		return userModel.MfaEnrollModel{}, err
+	}
+
+	// Повторное подключение заменяет неподтверждённый секрет
+	query = fmt.Sprintf(`INSERT INTO %s (users_id, secret, is_enabled, last_step, created_at) values ($1, $2, false, 0, $3)
+		ON CONFLICT (users_id) DO UPDATE SET secret = EXCLUDED.secret, is_enabled = false, last_step = 0,
+		created_at = EXCLUDED.created_at, confirmed_at = NULL`,
+		tableConstants.USERS_TOTP_TABLE,
+	)
+
+	if _, err := r.db.Exec(query, usersId, encryptedSecret, time.Now()); err != nil {
+		return userModel.MfaEnrollModel{}, err
+	}
+
+	return userModel.MfaEnrollModel{
+		Secret: secret,
+		Uri:    uri,
+		QrCode: authService.PngDataUri(image),
+	}, nil
+}
+
+/* Подтверждение подключения двухфакторной аутентификации первым кодом */
+func (r *MfaPostgres) ConfirmMfa(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaRecoveryCodesModel, error) {
+	totp, err := getUserTotp(r.db, usersId)
+	if err != nil {
+		return userModel.MfaRecoveryCodesModel{}, errors.New("Двухфакторная аутентификация не настроена!")
+	}
+
+	if totp.IsEnabled {
+		return userModel.MfaRecoveryCodesModel{}, errors.New("Двухфакторная аутентификация уже включена!")
+	}
+
+	var step int64
+	err = r.throttleMfaCode(usersId, session.Ip, func() error {
+		var ok bool
+		if step, ok = authService.ValidateTotpCode(totp.Secret, data.Code, totp.LastStep); !ok {
+			return errors.New("Неверный код двухфакторной аутентификации!")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return userModel.MfaRecoveryCodesModel{}, err
+	}
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.MfaRecoveryCodesModel{}, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET is_enabled=true, last_step=$1, confirmed_at=$2 WHERE id=$3", tableConstants.USERS_TOTP_TABLE)
+	if _, err := tx.Exec(query, step, time.Now(), totp.Id); err != nil {
+		tx.Rollback()
+		return userModel.MfaRecoveryCodesModel{}, err
+	}
+
+	codes, err := replaceRecoveryCodes(tx, usersId)
+	if err != nil {
+		tx.Rollback()
+		return userModel.MfaRecoveryCodesModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return userModel.MfaRecoveryCodesModel{}, err
+	}
+
+	return userModel.MfaRecoveryCodesModel{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+/* Отключение двухфакторной аутентификации (требуется действующий код) */
+func (r *MfaPostgres) DisableMfa(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaStatusModel, error) {
+	err := r.throttleMfaCode(usersId, session.Ip, func() error {
+		return verifyMfaCode(r.db, usersId, data.Code)
+	})
+	if err != nil {
+		return userModel.MfaStatusModel{}, err
+	}
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.MfaStatusModel{}, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstants.RECOVERY_CODES_TABLE)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		tx.Rollback()
+		return userModel.MfaStatusModel{}, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstants.USERS_TOTP_TABLE)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		tx.Rollback()
+		return userModel.MfaStatusModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return userModel.MfaStatusModel{}, err
+	}
+
+	return userModel.MfaStatusModel{
+		IsEnabled: false,
+	}, nil
+}
+
+/* Генерация нового набора кодов восстановления (предыдущие коды становятся недействительными) */
+func (r *MfaPostgres) RegenerateRecoveryCodes(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaRecoveryCodesModel, error) {
+	err := r.throttleMfaCode(usersId, session.Ip, func() error {
+		return verifyMfaCode(r.db, usersId, data.Code)
+	})
+	if err != nil {
+		return userModel.MfaRecoveryCodesModel{}, err
+	}
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.MfaRecoveryCodesModel{}, err
+	}
+
+	codes, err := replaceRecoveryCodes(tx, usersId)
+	if err != nil {
+		tx.Rollback()
+		return userModel.MfaRecoveryCodesModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return userModel.MfaRecoveryCodesModel{}, err
+	}
+
+	return userModel.MfaRecoveryCodesModel{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+/*
+* Verification of code of second factor with throttling of failed attempts per account and IP address
+* (a stolen access token is not enough to brute-force code for disabling of two-factor authentication)
+ */
+func (r *MfaPostgres) throttleMfaCode(usersId int, ip string, verify func() error) error {
+	var userEmail string
+	query := fmt.Sprintf("SELECT tl.email FROM %s tl WHERE tl.id = $1 LIMIT 1", tableConstants.USERS_TABLE)
+
+	if err := r.db.Get(&userEmail, query, usersId); err != nil {
+		return err
+	}
+
+	if err := checkAttempts(r.db, mfaAttemptsPolicy, userEmail, ip); err != nil {
+		return err
+	}
+
+	if verifyErr := verify(); verifyErr != nil {
+		if _, err := registerFailedAttempt(r.db, mfaAttemptsPolicy, userEmail, ip); err != nil {
+			return err
+		}
+
+		return verifyErr
+	}
+
+	return resetAttempts(r.db, mfaAttemptsPolicy, userEmail)
+}
+
+/* Getting TOTP secret of user (decrypted; secrets stored before encryption are returned as is) */
+func getUserTotp(db *sqlx.DB, usersId int) (userModel.UserTotpModel, error) {
+	var totp userModel.UserTotpModel
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.users_id = $1 LIMIT 1", tableConstants.USERS_TOTP_TABLE)
+
+	if err := db.Get(&totp, query, usersId); err != nil {
+		return totp, err
+	}
+
+	if !authService.IsEncryptedToken(totp.Secret) {
+		return totp, nil
+	}
+
+	secret, err := authService.DecryptToken(totp.Secret)
+	if err != nil {
+		return totp, err
+	}
+
+	totp.Secret = secret
+
+	return totp, nil
+}
+
+/* Checking that the two-factor authentication is enabled for user (secret is not decrypted) */
+func isMfaEnabled(db *sqlx.DB, usersId int) bool {
+	var isEnabled bool
+	query := fmt.Sprintf("SELECT tl.is_enabled FROM %s tl WHERE tl.users_id = $1 LIMIT 1", tableConstants.USERS_TOTP_TABLE)
+
+	err := db.Get(&isEnabled, query, usersId)
+
+	return err == nil && isEnabled
+}
+
+/*
+* Verification of second factor: TOTP code or one-time recovery code
+* (used time step of TOTP and used recovery code can not be presented again)
+ */
+func verifyMfaCode(db *sqlx.DB, usersId int, code string) error {
+	totp, err := getUserTotp(db, usersId)
+	if err != nil || !totp.IsEnabled {
+		return errors.New("Двухфакторная аутентификация не включена!")
+	}
+
+	if step, ok := authService.ValidateTotpCode(totp.Secret, code, totp.LastStep); ok {
+		query := fmt.Sprintf("UPDATE %s SET last_step=$1 WHERE id=$2 AND last_step < $1", tableConstants.USERS_TOTP_TABLE)
+
+		result, err := db.Exec(query, step, totp.Id)
+		if err != nil {
+			return err
+		}
+
+		if count, err := result.RowsAffected(); err == nil && count > 0 {
+			return nil
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET used_at=$1 WHERE users_id=$2 AND code_hash=$3 AND used_at IS NULL RETURNING id",
+		tableConstants.RECOVERY_CODES_TABLE,
+	)
+
+	var id int
+	err = db.QueryRow(query, time.Now(), usersId, hashToken(authService.NormalizeRecoveryCode(code))).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.New("Неверный код двухфакторной аутентификации!")
+	}
+
+	return err
+}
+
+/* Replacing recovery codes of user with a new set (only hashes of codes are stored) */
+func replaceRecoveryCodes(tx *sql.Tx, usersId int) ([]string, error) {
+	codes, err := authService.GenerateRecoveryCodes(authConstants.MFA_RECOVERY_CODES_COUNT)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstants.RECOVERY_CODES_TABLE)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (users_id, code_hash) values ($1, $2)", tableConstants.RECOVERY_CODES_TABLE)
+	for _, code := range codes {
+		if _, err := tx.Exec(query, usersId, hashToken(authService.NormalizeRecoveryCode(code))); err != nil {
+			return nil, err
+		}
+	}
+
+	return codes, nil
+}
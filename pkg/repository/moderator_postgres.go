@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	articleConstant "main-server/pkg/constant/article"
+	middlewareConstants "main-server/pkg/constant/middleware"
 	tableConstant "main-server/pkg/constant/table"
 	tableConstants "main-server/pkg/constant/table"
 	articleModel "main-server/pkg/model/article"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
@@ -14,12 +18,12 @@ import (
 /* Structure for this repository */
 type ModeratorPostgres struct {
 	db       *sqlx.DB
-	enforcer *casbin.Enforcer
+	enforcer *casbin.SyncedEnforcer
 	domain   *DomainPostgres
 }
 
 /* Function for create repository */
-func NewModeratorPostgres(db *sqlx.DB, enforcer *casbin.Enforcer, domain *DomainPostgres) *ModeratorPostgres {
+func NewModeratorPostgres(db *sqlx.DB, enforcer *casbin.SyncedEnforcer, domain *DomainPostgres) *ModeratorPostgres {
 	return &ModeratorPostgres{
 		db:       db,
 		enforcer: enforcer,
@@ -28,13 +32,17 @@ func NewModeratorPostgres(db *sqlx.DB, enforcer *casbin.Enforcer, domain *Domain
 }
 
 func (r *ModeratorPostgres) GetUncheckedArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleModel, error) {
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
+
 	var article articleModel.ArticleDBModel
 
-	query := fmt.Sprintf("SELECT * FROM %s as tl WHERE tl.uuid = $1 LIMIT 1",
+	query := fmt.Sprintf("SELECT * FROM %s AS a1 WHERE %s AND a1.uuid = $2 AND %s LIMIT 1",
 		tableConstant.ARTICLES_TABLE,
+		domainArticleCondition,
+		reviewArticleCondition,
 	)
 
-	err := r.db.Get(&article, query, uuid.Uuid)
+	err := r.db.Get(&article, query, domainsId, uuid.Uuid)
 	if err != nil {
 		return articleModel.ArticleModel{}, err
 	}
@@ -59,52 +67,172 @@ func (r *ModeratorPostgres) GetUncheckedArticle(uuid articleModel.ArticleUuidMod
 		Text:      article.Text,
 		Tags:      article.Tags,
 		Files:     articlesFiles,
+		Status:    article.Status,
+		PublishAt: article.PublishAt,
 		CreatedAt: article.CreatedAt,
 		UpdatedAt: article.UpdatedAt,
 	}, nil
 }
 
-func (r *ModeratorPostgres) GetUncheckedArticles(c *gin.Context) (articleModel.ArticlesModel, error) {
-	query := fmt.Sprintf(
-		"SELECT * FROM %s AS a1 WHERE not exists(SELECT * FROM %s AS a2 WHERE a2.articles_id = a1.id);",
+func (r *ModeratorPostgres) GetUncheckedArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error) {
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
+
+	return getArticlesPage(r.db, []string{domainArticleCondition, reviewArticleCondition}, []interface{}{domainsId}, filter)
+}
+
+/* Approve or reject unchecked article */
+func (r *ModeratorPostgres) CheckArticle(c *gin.Context, data articleModel.ArticleCheckRequestModel, isApproved bool) (articleModel.ArticleSuccessModel, error) {
+	usersId, _ := c.Get(middlewareConstants.USER_CTX)
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	// Блокировка статьи, чтобы параллельные решения модераторов не записали противоречивые проверки
+	// (состояние статьи проверяется отдельным запросом уже после получения блокировки)
+	query := fmt.Sprintf("SELECT id FROM %s WHERE uuid = $1 FOR UPDATE", tableConstant.ARTICLES_TABLE)
+	if _, err := tx.Exec(query, data.Uuid); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	var article articleModel.ArticleDBModel
+
+	// Модератор проверяет только статьи домена запроса
+	query = fmt.Sprintf("SELECT * FROM %s AS a1 WHERE %s AND a1.uuid = $2 AND %s LIMIT 1",
 		tableConstant.ARTICLES_TABLE,
+		domainArticleCondition,
+		reviewArticleCondition,
+	)
+
+	err = tx.Get(&article, query, domainsId, data.Uuid)
+	if err != nil {
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{}, errors.New("Статья не найдена или уже проверена!")
+	}
+
+	currentDate := time.Now()
+
+	query = fmt.Sprintf("INSERT INTO %s (articles_id, users_id, is_approved, reason, created_at) values ($1, $2, $3, $4, $5)",
 		tableConstant.ARTICLES_CHECKED_TABLE,
 	)
 
-	var articlesDb []articleModel.ArticleDBModel
-	err := r.db.Select(&articlesDb, query)
+	_, err = tx.Exec(query, article.Id, usersId, isApproved, data.Reason, currentDate)
+	if err != nil {
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{}, err
+	}
 
+	// Одобренная статья публикуется сразу, если время её публикации не задано или уже наступило
+	// (иначе статья будет опубликована планировщиком)
+	if isApproved && (article.PublishAt == nil || !article.PublishAt.After(currentDate)) {
+		query = fmt.Sprintf("UPDATE %s tl SET status=$1 WHERE tl.id = $2", tableConstant.ARTICLES_TABLE)
+
+		_, err = tx.Exec(query, articleConstant.STATUS_PUBLISHED, article.Id)
+		if err != nil {
+			tx.Rollback()
+			return articleModel.ArticleSuccessModel{}, err
+		}
+	}
+
+	err = tx.Commit()
 	if err != nil {
-		return articleModel.ArticlesModel{}, err
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{}, err
 	}
 
-	var articles articleModel.ArticlesModel
+	return articleModel.ArticleSuccessModel{
+		Success: true,
+	}, nil
+}
 
-	query = fmt.Sprintf(`SELECT index, filename, filepath FROM %s JOIN %s ON %s.files_id = %s.id WHERE %s.articles_id=$1;`,
-		tableConstant.ARTICLES_FILES_TABLE, tableConstant.FILES_TABLE,
-		tableConstant.ARTICLES_FILES_TABLE, tableConstant.FILES_TABLE,
-		tableConstant.ARTICLES_FILES_TABLE,
+/* Get history of moderator decisions for article */
+func (r *ModeratorPostgres) GetArticleChecks(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleCheckedHistoryModel, error) {
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
+
+	var article articleModel.ArticleDBModel
+
+	query := fmt.Sprintf("SELECT * FROM %s AS a1 WHERE %s AND a1.uuid = $2 LIMIT 1",
+		tableConstant.ARTICLES_TABLE,
+		domainArticleCondition,
 	)
 
-	for _, element := range articlesDb {
-		var files []articleModel.ArticlesFilesDBModel
-		err := r.db.Select(&files, query, element.Id)
+	err := r.db.Get(&article, query, domainsId, uuid.Uuid)
+	if err != nil {
+		return articleModel.ArticleCheckedHistoryModel{}, err
+	}
 
+	checks, err := getArticleChecks(r.db, article.Id)
+	if err != nil {
+		return articleModel.ArticleCheckedHistoryModel{}, err
+	}
+
+	return articleModel.ArticleCheckedHistoryModel{
+		Uuid:   article.Uuid,
+		Checks: checks,
+	}, nil
+}
+
+/* Get changes of article since its last approval */
+func (r *ModeratorPostgres) GetArticleChanges(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleRevisionDiffModel, error) {
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
+
+	var article articleModel.ArticleDBModel
+
+	query := fmt.Sprintf("SELECT * FROM %s AS a1 WHERE %s AND a1.uuid = $2 LIMIT 1",
+		tableConstant.ARTICLES_TABLE,
+		domainArticleCondition,
+	)
+
+	err := r.db.Get(&article, query, domainsId, uuid.Uuid)
+	if err != nil {
+		return articleModel.ArticleRevisionDiffModel{}, err
+	}
+
+	// Ревизия, действовавшая на момент последнего одобрения (пустая, если статья не одобрялась)
+	var from articleModel.ArticleRevisionModel
+
+	var approvedAt []time.Time
+
+	query = fmt.Sprintf("SELECT tl.created_at FROM %s tl WHERE tl.articles_id = $1 AND tl.is_approved = true ORDER BY tl.created_at DESC LIMIT 1",
+		tableConstant.ARTICLES_CHECKED_TABLE,
+	)
+
+	err = r.db.Select(&approvedAt, query, article.Id)
+	if err != nil {
+		return articleModel.ArticleRevisionDiffModel{}, err
+	}
+
+	if len(approvedAt) > 0 {
+		from, err = getArticleRevisionAt(r.db, article.Id, approvedAt[0])
 		if err != nil {
-			return articleModel.ArticlesModel{}, err
+			return articleModel.ArticleRevisionDiffModel{}, err
 		}
+	}
+
+	to, err := getArticleLastRevision(r.db, article.Id)
+	if err != nil {
+		return articleModel.ArticleRevisionDiffModel{}, err
+	}
+
+	return diffArticleRevisions(article.Uuid, from, to), nil
+}
 
-		articles.Articles = append(articles.Articles, articleModel.ArticleModel{
-			Uuid:      element.Uuid,
-			Filepath:  element.Filepath,
-			Title:     element.Title,
-			Text:      element.Text,
-			Tags:      element.Tags,
-			Files:     files,
-			CreatedAt: element.CreatedAt,
-			UpdatedAt: element.UpdatedAt,
-		})
+/* Get moderator decisions for article (newest first) */
+func getArticleChecks(db *sqlx.DB, articlesId int) ([]articleModel.ArticleCheckedModel, error) {
+	query := fmt.Sprintf(`SELECT u.uuid AS moderator_uuid, tl.is_approved, tl.reason, tl.created_at FROM %s tl 
+		LEFT JOIN %s u ON u.id = tl.users_id WHERE tl.articles_id = $1 ORDER BY tl.created_at DESC`,
+		tableConstant.ARTICLES_CHECKED_TABLE, tableConstant.USERS_TABLE,
+	)
+
+	var checks []articleModel.ArticleCheckedModel
+
+	err := db.Select(&checks, query, articlesId)
+	if err != nil {
+		return nil, err
 	}
 
-	return articles, nil
+	return checks, nil
 }
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"strconv"
+
+	"github.com/casbin/casbin/v2"
+)
+
+type PolicyPostgres struct {
+	enforcer *casbin.SyncedEnforcer
+}
+
+/* Create policy service */
+func NewPolicyPostgres(enforcer *casbin.SyncedEnforcer) *PolicyPostgres {
+	return &PolicyPostgres{
+		enforcer: enforcer,
+	}
+}
+
+/* Check permission of user for action with object in domain (model config/model.conf) */
+func (r *PolicyPostgres) Enforce(usersId, domainsId int, object, action string) (bool, error) {
+	return r.enforcer.Enforce(
+		strconv.Itoa(usersId),
+		strconv.Itoa(domainsId),
+		object,
+		action,
+	)
+}
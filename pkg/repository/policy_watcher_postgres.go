@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"fmt"
+	policyConstant "main-server/pkg/constant/policy"
+	"main-server/pkg/metric"
+	"sync"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+* Watcher of rules table, which notifies all server replicas about changes of policy
+* (notifications are sent by trigger of rules table through Postgres LISTEN/NOTIFY)
+ */
+type PolicyWatcherPostgres struct {
+	listener *pq.Listener
+	mutex    sync.Mutex
+	callback func(string)
+	done     chan struct{}
+}
+
+/* Create policy watcher */
+func NewPolicyWatcherPostgres(db *sqlx.DB, dsn, rulesTable string) (*PolicyWatcherPostgres, error) {
+	if err := checkPolicyNotifyTrigger(db, rulesTable); err != nil {
+		return nil, err
+	}
+
+	listener := pq.NewListener(dsn,
+		policyConstant.POLICY_LISTENER_MIN_RECONNECT,
+		policyConstant.POLICY_LISTENER_MAX_RECONNECT,
+		func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				logrus.Errorf("policy listener: %s", err.Error())
+			}
+		},
+	)
+
+	if err := listener.Listen(policyConstant.POLICY_NOTIFY_CHANNEL); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	watcher := &PolicyWatcherPostgres{
+		listener: listener,
+		done:     make(chan struct{}),
+	}
+
+	go watcher.run()
+
+	return watcher, nil
+}
+
+/*
+* Checking that the trigger, which sends notification on any change of rules table, exists
+* (trigger is created by migration of schema)
+ */
+func checkPolicyNotifyTrigger(db *sqlx.DB, rulesTable string) error {
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = $1 AND tgrelid = to_regclass($2))"
+
+	if err := db.Get(&exists, query, policyConstant.POLICY_NOTIFY_TRIGGER, rulesTable); err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("trigger %s of rules table %s is not found", policyConstant.POLICY_NOTIFY_TRIGGER, rulesTable)
+	}
+
+	return nil
+}
+
+func (w *PolicyWatcherPostgres) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case notification := <-w.listener.Notify:
+			// После переподключения приходит пустое уведомление - изменения могли быть пропущены
+			payload := ""
+			if notification != nil {
+				payload = notification.Extra
+			}
+
+			metric.PolicyNotifications.Add(1)
+
+			w.mutex.Lock()
+			callback := w.callback
+			w.mutex.Unlock()
+
+			if callback != nil {
+				callback(payload)
+			}
+		}
+	}
+}
+
+/* Set the function, which is called on change of policy */
+func (w *PolicyWatcherPostgres) SetUpdateCallback(callback func(string)) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.callback = callback
+
+	return nil
+}
+
+/* Notification is sent by trigger of rules table, so there is nothing to do here */
+func (w *PolicyWatcherPostgres) Update() error {
+	return nil
+}
+
+/* Stop the watcher */
+func (w *PolicyWatcherPostgres) Close() {
+	close(w.done)
+	w.listener.Close()
+}
+
+/* Reload policy of enforcer from database (with accounting in metrics) */
+func ReloadPolicy(enforcer *casbin.SyncedEnforcer) {
+	metric.PolicyReloads.Add(1)
+
+	if err := enforcer.LoadPolicy(); err != nil {
+		metric.PolicyReloadErrors.Add(1)
+		logrus.Errorf("failed to reload policy: %s", err.Error())
+	}
+}
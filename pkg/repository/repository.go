@@ -4,6 +4,7 @@ import (
 	articleModel "main-server/pkg/model/article"
 	rbacModel "main-server/pkg/model/rbac"
 	userModel "main-server/pkg/model/user"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
@@ -13,20 +14,24 @@ import (
 
 type Authorization interface {
 	// Main routes for user authenticated
-	CreateUser(user userModel.UserRegisterModel) (userModel.UserAuthDataModel, error)
-	LoginUser(user userModel.UserLoginModel) (userModel.UserAuthDataModel, error)
-	LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error)
-	CreateUserOAuth2(user userModel.UserRegisterOAuth2Model, token *oauth2.Token) (userModel.UserAuthDataModel, error)
-	Refresh(data userModel.TokenLogoutDataModel, refreshToken string, token userModel.TokenOutputParse) (userModel.UserAuthDataModel, error)
+	CreateUser(user userModel.UserRegisterModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
+	LoginUser(user userModel.UserLoginModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
+	LoginUserProvider(provider, code string, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
+	CreateUserProvider(user userModel.ProviderUserModel, provider string, token *oauth2.Token, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
+	LoginUserMfa(data userModel.UserMfaLoginModel, token userModel.MfaTokenOutputParse, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
+	Refresh(data userModel.TokenLogoutDataModel, refreshToken string, token userModel.TokenOutputParse, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
 	Logout(tokens userModel.TokenLogoutDataModel) (bool, error)
 	Activate(link string) (bool, error)
+	ResendActivation(email string, session userModel.SessionDataModel) (bool, error)
+	IsActivated(usersId int) (bool, error)
+	UnlockAccount(link string) (bool, error)
 
 	// Get user information
 	GetUser(column, value string) (userModel.UserModel, error)
 	GetRole(column, value string) (rbacModel.RoleModel, error)
 
 	// Recovery password
-	RecoveryPassword(email string) (bool, error)
+	RecoveryPassword(email string, session userModel.SessionDataModel) (bool, error)
 	ResetPassword(data userModel.ResetPasswordModel, token userModel.ResetTokenOutputParse) (bool, error)
 }
 
@@ -35,6 +40,10 @@ type Role interface {
 	HasRole(usersId, domainsId int, roleValue string) (bool, error)
 }
 
+type Policy interface {
+	Enforce(usersId, domainsId int, object, action string) (bool, error)
+}
+
 type Domain interface {
 	GetDomain(column, value interface{}) (rbacModel.DomainModel, error)
 }
@@ -47,7 +56,21 @@ type User interface {
 	UpdateArticle(c *gin.Context, data articleModel.ArticleUpdateRequestModel) (bool, error)
 	DeleteArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
 	GetArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleModel, error)
-	GetArticles(c *gin.Context) (articleModel.ArticlesModel, error)
+	GetArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error)
+	GetTrashArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error)
+	RestoreArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+	UpdateArticleStatus(data articleModel.ArticleStatusRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+	SearchArticles(c *gin.Context, search articleModel.ArticlesSearchRequestModel) (articleModel.ArticlesSearchModel, error)
+
+	// Article revisions
+	GetArticleRevisions(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleRevisionsModel, error)
+	GetArticleRevisionsDiff(data articleModel.ArticleRevisionDiffRequestModel, c *gin.Context) (articleModel.ArticleRevisionDiffModel, error)
+	RestoreArticleRevision(data articleModel.ArticleRevisionRestoreRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+
+	// Article sharing
+	GrantArticleAccess(data articleModel.ArticleGrantRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+	RevokeArticleAccess(data articleModel.ArticleRevokeRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+	GetArticleGrants(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleGrantsModel, error)
 
 	// Profile
 	GetProfile(c *gin.Context) (userModel.UserProfileModel, error)
@@ -56,17 +79,80 @@ type User interface {
 
 type Moderator interface {
 	GetUncheckedArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleModel, error)
-	GetUncheckedArticles(c *gin.Context) (articleModel.ArticlesModel, error)
+	GetUncheckedArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error)
+	CheckArticle(c *gin.Context, data articleModel.ArticleCheckRequestModel, isApproved bool) (articleModel.ArticleSuccessModel, error)
+	GetArticleChecks(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleCheckedHistoryModel, error)
+	GetArticleChanges(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleRevisionDiffModel, error)
+}
+
+type Admin interface {
+	// Domains
+	CreateDomain(c *gin.Context, data rbacModel.DomainCreateRequestModel) (rbacModel.DomainModel, error)
+	UpdateDomain(c *gin.Context, data rbacModel.DomainUpdateRequestModel) (rbacModel.DomainModel, error)
+	DeleteDomain(c *gin.Context, data rbacModel.DomainUuidModel) (rbacModel.RbacSuccessModel, error)
+	GetDomain(c *gin.Context, data rbacModel.DomainUuidModel) (rbacModel.DomainModel, error)
+	GetDomains(c *gin.Context) (rbacModel.DomainsModel, error)
+
+	// Roles
+	CreateRole(c *gin.Context, data rbacModel.RoleCreateRequestModel) (rbacModel.RoleModel, error)
+	UpdateRole(c *gin.Context, data rbacModel.RoleUpdateRequestModel) (rbacModel.RoleModel, error)
+	DeleteRole(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RbacSuccessModel, error)
+	GetRole(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RoleModel, error)
+	GetRoles(c *gin.Context, data rbacModel.RolesRequestModel) (rbacModel.RolesModel, error)
+
+	// Role assignments
+	AssignRole(c *gin.Context, data rbacModel.RoleAssignRequestModel) (rbacModel.RbacSuccessModel, error)
+	RemoveRole(c *gin.Context, data rbacModel.RoleAssignRequestModel) (rbacModel.RbacSuccessModel, error)
+	GetRoleUsers(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RoleUsersModel, error)
+
+	// Users
+	ForceLogout(c *gin.Context, data rbacModel.UserUuidModel) (userModel.SessionRevokeModel, error)
 }
 
 type Guest interface {
-	GetArticles() (articleModel.ArticlesModel, error)
+	GetArticles(domainsId int, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error)
+	GetArticle(domainsId int, uuid articleModel.ArticleUuidModel) (articleModel.ArticleModel, error)
+	SearchArticles(domainsId int, search articleModel.ArticlesSearchRequestModel) (articleModel.ArticlesSearchModel, error)
+
+	// Tags
+	GetTags(domainsId int) (articleModel.TagsModel, error)
+	GetArticlesByTag(domainsId int, data articleModel.ArticlesByTagRequestModel) (articleModel.ArticlesModel, error)
+}
+
+type Scheduler interface {
+	PublishScheduledArticles() (int64, error)
+	PurgeDeletedArticles(retention time.Duration) (int64, error)
+}
+
+type Session interface {
+	GetSessions(usersId int, current string) (userModel.SessionsModel, error)
+	RevokeSession(usersId int, uuid string) (userModel.SessionRevokeModel, error)
+	RevokeOtherSessions(usersId int, current string) (userModel.SessionRevokeModel, error)
+}
+
+type Mfa interface {
+	EnrollMfa(usersId int) (userModel.MfaEnrollModel, error)
+	ConfirmMfa(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaRecoveryCodesModel, error)
+	DisableMfa(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaStatusModel, error)
+	RegenerateRecoveryCodes(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaRecoveryCodesModel, error)
+}
+
+type AuthMethod interface {
+	GetAuthMethods(usersId int) (userModel.AuthMethodsModel, error)
+	LinkAuthMethod(usersId int, provider, code string) (userModel.AuthMethodsModel, error)
+	UnlinkAuthMethod(usersId int, value string) (userModel.AuthMethodsModel, error)
+	SetPassword(usersId int, data userModel.SetPasswordModel) (userModel.AuthMethodsModel, error)
+	VerifyAuthMethod(usersId int, value string) (bool, error)
 }
 
 type AuthType interface {
 	GetAuthType(column, value interface{}) (userModel.AuthTypeModel, error)
 }
 
+type TokenRevocation interface {
+	IsTokenRevoked(tokenId string) (bool, error)
+}
+
 type Repository struct {
 	Authorization
 	Role
@@ -75,20 +161,34 @@ type Repository struct {
 	Moderator
 	AuthType
 	Guest
+	Scheduler
+	Policy
+	Admin
+	Session
+	Mfa
+	AuthMethod
+	TokenRevocation
 }
 
-func NewRepository(db *sqlx.DB, enforcer *casbin.Enforcer) *Repository {
+func NewRepository(db *sqlx.DB, enforcer *casbin.SyncedEnforcer) *Repository {
 	domain := NewDomainPostgres(db)
 	user := NewUserPostgres(db, enforcer, domain)
 	moderator := NewModeratorPostgres(db, enforcer, domain)
 
 	return &Repository{
-		Authorization: NewAuthPostgres(db, enforcer, *user),
-		Role:          NewRolePostgres(db, enforcer),
-		Domain:        domain,
-		User:          user,
-		Moderator:     moderator,
-		AuthType:      NewAuthTypePostgres(db),
-		Guest:         NewGuestPostgres(db),
+		Authorization:   NewAuthPostgres(db, enforcer, *user),
+		Role:            NewRolePostgres(db, enforcer),
+		Domain:          domain,
+		User:            user,
+		Moderator:       moderator,
+		AuthType:        NewAuthTypePostgres(db),
+		Guest:           NewGuestPostgres(db),
+		Scheduler:       NewSchedulerPostgres(db, enforcer),
+		Policy:          NewPolicyPostgres(enforcer),
+		Admin:           NewAdminPostgres(db, enforcer, domain),
+		Session:         NewSessionPostgres(db),
+		Mfa:             NewMfaPostgres(db),
+		AuthMethod:      NewAuthMethodPostgres(db),
+		TokenRevocation: NewTokenRevocationPostgres(db),
 	}
 }
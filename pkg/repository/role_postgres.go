@@ -12,11 +12,11 @@ import (
 
 type RolePostgres struct {
 	db       *sqlx.DB
-	enforcer *casbin.Enforcer
+	enforcer *casbin.SyncedEnforcer
 }
 
 /* Create role service */
-func NewRolePostgres(db *sqlx.DB, enforcer *casbin.Enforcer) *RolePostgres {
+func NewRolePostgres(db *sqlx.DB, enforcer *casbin.SyncedEnforcer) *RolePostgres {
 	return &RolePostgres{
 		db:       db,
 		enforcer: enforcer,
@@ -43,13 +43,16 @@ func (r *RolePostgres) GetRole(column, value interface{}) (rbacModel.RoleModel,
 }
 
 func (r *RolePostgres) HasRole(usersId, domainsId int, roleValue string) (bool, error) {
-	data, err := r.GetRole("value", roleValue)
+	// Роли с одинаковым значением могут существовать в разных доменах
+	var data rbacModel.RoleModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE value=$1 AND domains_id=$2 LIMIT 1", tableConstants.ROLES_TABLE)
 
+	err := r.db.Get(&data, query, roleValue, domainsId)
 	if err != nil {
 		return false, err
 	}
 
-	r.enforcer.LoadPolicy()
+	// Политика хранится в памяти и перезагружается наблюдателем при изменении таблицы правил
 	has, err := r.enforcer.HasRoleForUser(
 		strconv.Itoa(usersId),
 		strconv.Itoa(data.Id),
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"fmt"
+	articleConstant "main-server/pkg/constant/article"
+	tableConstants "main-server/pkg/constant/table"
+	articleModel "main-server/pkg/model/article"
+	"os"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type SchedulerPostgres struct {
+	db       *sqlx.DB
+	enforcer *casbin.SyncedEnforcer
+}
+
+/*
+* Функция создания экземпляра сервиса
+ */
+func NewSchedulerPostgres(db *sqlx.DB, enforcer *casbin.SyncedEnforcer) *SchedulerPostgres {
+	return &SchedulerPostgres{
+		db:       db,
+		enforcer: enforcer,
+	}
+}
+
+/*
+* Функция публикации одобренных статей, время публикации которых наступило
+* (время обновления статей не изменяется, чтобы не сбросить решение модератора)
+ */
+func (r *SchedulerPostgres) PublishScheduledArticles() (int64, error) {
+	query := fmt.Sprintf("UPDATE %s AS a1 SET status = $1 WHERE a1.status = $2 AND a1.publish_at <= $3 AND %s AND %s",
+		tableConstants.ARTICLES_TABLE,
+		activeArticleCondition,
+		approvedArticleCondition,
+	)
+
+	result, err := r.db.Exec(query, articleConstant.STATUS_PUBLISHED, articleConstant.STATUS_REVIEW, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+/*
+* Функция окончательного удаления статей, находящихся в корзине дольше периода хранения
+ */
+func (r *SchedulerPostgres) PurgeDeletedArticles(retention time.Duration) (int64, error) {
+	var articles []articleModel.ArticleDBModel
+
+	query := fmt.Sprintf("SELECT * FROM %s AS a1 WHERE %s AND a1.deleted_at <= $1",
+		tableConstants.ARTICLES_TABLE,
+		deletedArticleCondition,
+	)
+
+	err := r.db.Select(&articles, query, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64 = 0
+
+	for _, article := range articles {
+		if err := r.purgeArticle(article); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+/*
+* Функция удаления статьи вместе с её файлами, ревизиями и политиками доступа
+ */
+func (r *SchedulerPostgres) purgeArticle(article articleModel.ArticleDBModel) error {
+	// Файлы всех ревизий статьи (включая текущее состояние статьи)
+	filepaths, err := getArticleRevisionsFilepaths(r.db, article.Id)
+	if err != nil {
+		return err
+	}
+
+	var articlesFiles []articleModel.ArticlesFilesDBModel
+
+	query := fmt.Sprintf(`SELECT files_id, index, filename, filepath FROM %s JOIN %s ON %s.files_id = %s.id WHERE %s.articles_id=$1;`,
+		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
+		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
+		tableConstants.ARTICLES_FILES_TABLE,
+	)
+
+	err = r.db.Select(&articlesFiles, query, article.Id)
+	if err != nil {
+		return err
+	}
+
+	filepaths = append(filepaths, article.Filepath)
+	for _, element := range articlesFiles {
+		filepaths = append(filepaths, element.Filepath)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`WITH deleted AS (DELETE FROM %s tl WHERE tl.articles_id = $1 RETURNING tl.files_id)
+		DELETE FROM %s tl WHERE tl.id IN (SELECT files_id FROM deleted)`,
+		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
+	)
+
+	_, err = tx.Exec(query, article.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = $1", tableConstants.ARTICLES_TABLE)
+
+	_, err = tx.Exec(query, article.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.value = $1", tableConstants.OBJECTS_TABLE)
+
+	_, err = tx.Exec(query, article.Uuid)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Удаление политик доступа к статье (после фиксации транзакции, т.к. изменения политики
+	// не отменяются откатом транзакции; ресурс находится на третьей позиции правила)
+	_, policyErr := r.enforcer.RemoveFilteredPolicy(2, article.Uuid)
+
+	// Удаление файлов с диска выполняется после удаления записей о них
+	removed := make(map[string]bool)
+	for _, path := range filepaths {
+		if path == "" || removed[path] {
+			continue
+		}
+
+		removed[path] = true
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logrus.Warnf("failed to remove file of purged article %s: %s", article.Uuid, err.Error())
+		}
+	}
+
+	return policyErr
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SessionPostgres struct {
+	db *sqlx.DB
+}
+
+/*
+* Функция создания экземпляра сервиса
+ */
+func NewSessionPostgres(db *sqlx.DB) *SessionPostgres {
+	return &SessionPostgres{
+		db: db,
+	}
+}
+
+/* Получение всех сессий (устройств) пользователя */
+func (r *SessionPostgres) GetSessions(usersId int, current string) (userModel.SessionsModel, error) {
+	query := fmt.Sprintf(`SELECT uuid, device, user_agent, ip, created_at, last_seen_at FROM %s tl
+		WHERE tl.users_id = $1 ORDER BY tl.last_seen_at DESC`,
+		tableConstants.TOKENS_TABLE,
+	)
+
+	var sessions []userModel.SessionModel
+
+	err := r.db.Select(&sessions, query, usersId)
+	if err != nil {
+		return userModel.SessionsModel{}, err
+	}
+
+	for index := range sessions {
+		sessions[index].IsCurrent = sessions[index].Uuid == current
+	}
+
+	return userModel.SessionsModel{
+		Sessions: sessions,
+	}, nil
+}
+
+/* Завершение одной сессии пользователя (токен доступа сессии отзывается) */
+func (r *SessionPostgres) RevokeSession(usersId int, uuid string) (userModel.SessionRevokeModel, error) {
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	count, err := deleteSessions(tx, "tl.uuid::text = $1 AND tl.users_id = $2", uuid, usersId)
+	if err != nil {
+		tx.Rollback()
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	if count == 0 {
+		tx.Rollback()
+		return userModel.SessionRevokeModel{}, errors.New("Сессии не существует!")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	return userModel.SessionRevokeModel{
+		Count: count,
+	}, nil
+}
+
+/* Завершение всех сессий пользователя, кроме текущей (токены доступа сессий отзываются) */
+func (r *SessionPostgres) RevokeOtherSessions(usersId int, current string) (userModel.SessionRevokeModel, error) {
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	count, err := deleteSessions(tx, "tl.users_id = $1 AND tl.uuid::text <> $2", usersId, current)
+	if err != nil {
+		tx.Rollback()
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return userModel.SessionRevokeModel{}, err
+	}
+
+	return userModel.SessionRevokeModel{
+		Count: count,
+	}, nil
+}
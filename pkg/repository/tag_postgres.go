@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"main-server/pkg/constant"
+	articleConstant "main-server/pkg/constant/article"
+	tableConstants "main-server/pkg/constant/table"
+	articleModel "main-server/pkg/model/article"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/* Normalization of tag (trimming, lower case, single spaces) */
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+/* Splitting a free-form string into unique normalized tags (keeping their order) */
+func normalizeTags(tags string) ([]string, error) {
+	result := make([]string, 0)
+	exists := make(map[string]bool)
+
+	for _, element := range strings.Split(tags, constant.SEPARATOR) {
+		tag := normalizeTag(element)
+
+		if tag == "" || exists[tag] {
+			continue
+		}
+
+		if utf8.RuneCountInString(tag) > articleConstant.TAG_MAX_LENGTH {
+			return nil, errors.New(fmt.Sprintf("Длина тега не должна превышать %d символов!", articleConstant.TAG_MAX_LENGTH))
+		}
+
+		exists[tag] = true
+		result = append(result, tag)
+	}
+
+	return result, nil
+}
+
+/* Replacing the set of tags of article (tags are created if they do not exist) */
+func setArticleTags(tx *sql.Tx, articlesId int, tags []string) error {
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.articles_id = $1", tableConstants.ARTICLES_TAGS_TABLE)
+
+	_, err := tx.Exec(query, articlesId)
+	if err != nil {
+		return err
+	}
+
+	// Обновление при конфликте необходимо для получения ID уже существующего тега
+	queryTag := fmt.Sprintf(`INSERT INTO %s (value) values ($1)
+		ON CONFLICT (value) DO UPDATE SET value = EXCLUDED.value RETURNING id`, tableConstants.TAGS_TABLE)
+	queryLink := fmt.Sprintf("INSERT INTO %s (articles_id, tags_id) values ($1, $2)", tableConstants.ARTICLES_TAGS_TABLE)
+
+	for _, tag := range tags {
+		var tagId int
+
+		row := tx.QueryRow(queryTag, tag)
+		if err := row.Scan(&tagId); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(queryLink, articlesId, tagId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+* Getting tags with number of articles satisfying the conditions
+* (conditions use the alias a1 and arguments args)
+ */
+func getTagsCloud(db *sqlx.DB, conditions []string, args []interface{}) (articleModel.TagsModel, error) {
+	where := "true"
+	if len(conditions) > 0 {
+		where = strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`SELECT tg.value, count(*) AS count FROM %s AS tg
+		JOIN %s AS at ON at.tags_id = tg.id
+		JOIN %s AS a1 ON a1.id = at.articles_id
+		WHERE %s GROUP BY tg.value ORDER BY count DESC, tg.value`,
+		tableConstants.TAGS_TABLE, tableConstants.ARTICLES_TAGS_TABLE, tableConstants.ARTICLES_TABLE,
+		where,
+	)
+
+	tags := articleModel.TagsModel{
+		Tags: []articleModel.TagCountModel{},
+	}
+
+	err := db.Select(&tags.Tags, query, args...)
+	if err != nil {
+		return articleModel.TagsModel{}, err
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"main-server/pkg/constant"
+	articleConstant "main-server/pkg/constant/article"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/* Tag is trimmed, lowered and its inner spaces are collapsed */
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{name: "empty", tag: "", want: ""},
+		{name: "spaces only", tag: "   ", want: ""},
+		{name: "trimmed", tag: "  go  ", want: "go"},
+		{name: "lower case", tag: "GoLang", want: "golang"},
+		{name: "cyrillic", tag: "Новости", want: "новости"},
+		{name: "inner spaces", tag: "машинное \t  обучение", want: "машинное обучение"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeTag(tt.tag); got != tt.want {
+				t.Errorf("normalizeTag(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+/* Free-form string is split into unique normalized tags in order of their first appearance */
+func TestNormalizeTags(t *testing.T) {
+	join := func(tags ...string) string {
+		return strings.Join(tags, constant.SEPARATOR)
+	}
+
+	tests := []struct {
+		name    string
+		tags    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", tags: "", want: []string{}},
+		{name: "empty elements", tags: join("", " ", ""), want: []string{}},
+		{name: "single", tags: "Go", want: []string{"go"}},
+		{name: "order is kept", tags: join("b", "a", "c"), want: []string{"b", "a", "c"}},
+		{name: "duplicates after normalization", tags: join("Go", " go ", "GO", "news"), want: []string{"go", "news"}},
+		{name: "maximum length", tags: strings.Repeat("я", articleConstant.TAG_MAX_LENGTH), want: []string{strings.Repeat("я", articleConstant.TAG_MAX_LENGTH)}},
+		{name: "too long", tags: join("go", strings.Repeat("я", articleConstant.TAG_MAX_LENGTH+1)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTags(tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeTags(%q) error = %v, wantErr %v", tt.tags, err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeTags(%q) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/jmoiron/sqlx"
+)
+
+type TokenRevocationPostgres struct {
+	db *sqlx.DB
+}
+
+/*
+* Функция создания экземпляра сервиса
+ */
+func NewTokenRevocationPostgres(db *sqlx.DB) *TokenRevocationPostgres {
+	return &TokenRevocationPostgres{
+		db: db,
+	}
+}
+
+/* Проверка отзыва токена доступа по его идентификатору (jti) */
+func (r *TokenRevocationPostgres) IsTokenRevoked(tokenId string) (bool, error) {
+	var revoked bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s tl WHERE tl.jti = $1 AND tl.expires_at > $2)", tableConstants.REVOKED_TOKENS_TABLE)
+
+	err := r.db.Get(&revoked, query, tokenId, time.Now())
+
+	return revoked, err
+}
+
+/*
+* Revoking of one access token by its identifier (jti)
+* (token is kept in revocation list not longer than lifetime of access token, expired entries are removed here)
+ */
+func revokeAccessToken(tx *sql.Tx, tokenId string) error {
+	if tokenId == "" {
+		return nil
+	}
+
+	currentDate := time.Now()
+
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.expires_at <= $1", tableConstants.REVOKED_TOKENS_TABLE)
+	if _, err := tx.Exec(query, currentDate); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (jti, expires_at) values ($1, $2) ON CONFLICT (jti) DO NOTHING", tableConstants.REVOKED_TOKENS_TABLE)
+	_, err := tx.Exec(query, tokenId, currentDate.Add(authConstants.TOKEN_TLL_ACCESS))
+
+	return err
+}
+
+/*
+* Revoking of all tokens of user: version of access tokens is incremented
+* and all sessions are removed (so refresh tokens can not be used too)
+ */
+func revokeUserTokens(tx *sql.Tx, usersId int) (int64, error) {
+	query := fmt.Sprintf("UPDATE %s SET token_version = token_version + 1 WHERE id=$1", tableConstants.USERS_TABLE)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		return 0, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.users_id = $1", tableConstants.TOKENS_TABLE)
+
+	result, err := tx.Exec(query, usersId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+/* Getting identifier (jti) of access token, which is stored in session */
+func accessTokenId(accessToken string) string {
+	var claims tokenClaims
+
+	if _, _, err := new(jwt.Parser).ParseUnverified(accessToken, &claims); err != nil {
+		return ""
+	}
+
+	return claims.Id
+}
+
+/*
+* Removing of sessions, which match the condition, with revoking of their access tokens
+* (otherwise access token of removed session is valid until its expiration)
+ */
+func deleteSessions(tx *sql.Tx, condition string, args ...interface{}) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE %s RETURNING tl.access_token", tableConstants.TOKENS_TABLE, condition)
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	accessTokens := make([]string, 0)
+	for rows.Next() {
+		var accessToken string
+		if err := rows.Scan(&accessToken); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		accessTokens = append(accessTokens, accessToken)
+	}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, accessToken := range accessTokens {
+		if err := revokeAccessToken(tx, accessTokenId(accessToken)); err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(accessTokens)), nil
+}
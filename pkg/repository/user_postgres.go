@@ -2,15 +2,17 @@ package repository
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"main-server/pkg/constant"
 	actionConstant "main-server/pkg/constant/action"
+	articleConstant "main-server/pkg/constant/article"
 	middlewareConstants "main-server/pkg/constant/middleware"
 	objectConstant "main-server/pkg/constant/object"
 	tableConstants "main-server/pkg/constant/table"
 	articleModel "main-server/pkg/model/article"
 	rbacModel "main-server/pkg/model/rbac"
 	userModel "main-server/pkg/model/user"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -18,19 +20,21 @@ import (
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
 )
 
 type UserPostgres struct {
 	db       *sqlx.DB
-	enforcer *casbin.Enforcer
+	enforcer *casbin.SyncedEnforcer
 	domain   *DomainPostgres
 }
 
 /*
 * Функция создания экземпляра сервиса
  */
-func NewUserPostgres(db *sqlx.DB, enforcer *casbin.Enforcer, domain *DomainPostgres) *UserPostgres {
+func NewUserPostgres(db *sqlx.DB, enforcer *casbin.SyncedEnforcer, domain *DomainPostgres) *UserPostgres {
 	return &UserPostgres{
 		db:       db,
 		enforcer: enforcer,
@@ -62,23 +66,45 @@ func (r *UserPostgres) CreateArticle(c *gin.Context, data articleModel.ArticleCr
 	usersId, _ := c.Get(middlewareConstants.USER_CTX)
 	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
 
+	// Нормализация тегов статьи
+	tags, err := normalizeTags(data.Tags)
+	if err != nil {
+		return false, err
+	}
+
+	// Новая статья может быть сохранена как черновик или сразу отправлена на проверку
+	status := data.Status
+	if status == "" {
+		status = articleConstant.STATUS_REVIEW
+	}
+
+	if status != articleConstant.STATUS_DRAFT && status != articleConstant.STATUS_REVIEW {
+		return false, errors.New("Некорректный статус новой статьи!")
+	}
+
 	tx, err := r.db.Begin()
 	if err != nil {
 		return false, err
 	}
 
 	// Добавление общей информации о статье
-	query := fmt.Sprintf("INSERT INTO %s (uuid, users_id, title, filename, filepath, text, tags, created_at, updated_at) values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id", tableConstants.ARTICLES_TABLE)
+	query := fmt.Sprintf("INSERT INTO %s (uuid, users_id, domains_id, title, filename, filepath, text, tags, created_at, updated_at, status, publish_at) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id", tableConstants.ARTICLES_TABLE)
 	var articleId int
 	currentDate := time.Now()
 	articleUuid := uuid.NewV4()
 
-	row := tx.QueryRow(query, articleUuid, usersId, data.Title, data.Filename, data.Filepath, data.Text, data.Tags, currentDate, currentDate)
+	row := tx.QueryRow(query, articleUuid, usersId, domainsId, data.Title, data.Filename, data.Filepath, data.Text, strings.Join(tags, constant.SEPARATOR), currentDate, currentDate, status, data.PublishAt)
 	if err := row.Scan(&articleId); err != nil {
 		tx.Rollback()
 		return false, err
 	}
 
+	// Связывание статьи с тегами
+	if err := setArticleTags(tx, articleId, tags); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
 	// Добавление файлов статьи
 	query = fmt.Sprintf("INSERT INTO %s (filename, filepath) values ($1, $2) RETURNING id", tableConstants.FILES_TABLE)
 	var filesId []articleModel.FileArticleExModel
@@ -129,10 +155,24 @@ func (r *UserPostgres) CreateArticle(c *gin.Context, data articleModel.ArticleCr
 		return false, err
 	}
 
+	// Сохранение первой ревизии статьи
+	err = saveArticleRevision(tx, articleId, usersId)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
 	var userId string = strconv.Itoa(usersId.(int))
 	var domainId string = strconv.Itoa(domainsId.(int))
 
-	// Обновление политик доступа для текущего пользователя
+	// Обновление политик доступа для текущего пользователя (после фиксации транзакции, т.к. изменения
+	// политики не отменяются откатом транзакции)
 	_, err = r.enforcer.AddPolicies([][]string{
 		{userId, domainId, articleUuid.String(), actionConstant.DELETE},
 		{userId, domainId, articleUuid.String(), actionConstant.MODIFY},
@@ -140,13 +180,6 @@ func (r *UserPostgres) CreateArticle(c *gin.Context, data articleModel.ArticleCr
 	})
 
 	if err != nil {
-		tx.Rollback()
-		return false, err
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		tx.Rollback()
 		return false, err
 	}
 
@@ -157,17 +190,25 @@ func (r *UserPostgres) CreateArticle(c *gin.Context, data articleModel.ArticleCr
 func (r *UserPostgres) UpdateArticle(c *gin.Context, data articleModel.ArticleUpdateRequestModel) (bool, error) {
 	usersId, _ := c.Get(middlewareConstants.USER_CTX)
 
-	var article articleModel.ArticleDBModel
-
-	query := fmt.Sprintf("SELECT * FROM %s WHERE uuid=$1 AND users_id = $2", tableConstants.ARTICLES_TABLE)
+	// Нормализация тегов статьи
+	tags, err := normalizeTags(data.Tags)
+	if err != nil {
+		return false, err
+	}
 
-	err := r.db.Get(&article, query, data.Uuid, usersId)
+	tx, err := r.db.Beginx()
 	if err != nil {
 		return false, err
 	}
 
-	tx, err := r.db.Begin()
+	var article articleModel.ArticleDBModel
+
+	// Права на изменение статьи проверяются по политикам доступа (middleware articleEnforce)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE uuid=$1 AND deleted_at IS NULL FOR UPDATE", tableConstants.ARTICLES_TABLE)
+
+	err = tx.Get(&article, query, data.Uuid)
 	if err != nil {
+		tx.Rollback()
 		return false, err
 	}
 
@@ -188,8 +229,7 @@ func (r *UserPostgres) UpdateArticle(c *gin.Context, data articleModel.ArticleUp
 		args = append(args, *data.Filepath)
 		argId++
 
-		// Удалениие предыдущего изображения статьи
-		os.Remove(article.Filepath)
+		// Предыдущее изображение статьи не удаляется, так как на него ссылаются ревизии статьи
 	}
 
 	setValues = append(setValues, fmt.Sprintf("text=$%d", argId))
@@ -197,7 +237,7 @@ func (r *UserPostgres) UpdateArticle(c *gin.Context, data articleModel.ArticleUp
 	argId++
 
 	setValues = append(setValues, fmt.Sprintf("tags=$%d", argId))
-	args = append(args, data.Tags)
+	args = append(args, strings.Join(tags, constant.SEPARATOR))
 	argId++
 
 	// Изменение времени обновления статья
@@ -205,21 +245,33 @@ func (r *UserPostgres) UpdateArticle(c *gin.Context, data articleModel.ArticleUp
 	args = append(args, time.Now())
 	argId++
 
+	// Изменённая опубликованная статья снова отправляется на проверку
+	if article.Status == articleConstant.STATUS_PUBLISHED {
+		setValues = append(setValues, fmt.Sprintf("status=$%d", argId))
+		args = append(args, articleConstant.STATUS_REVIEW)
+		argId++
+	}
+
 	setQuery := strings.Join(setValues, ", ")
 
-	query = fmt.Sprintf("UPDATE %s tl SET %s WHERE tl.uuid = $%d AND tl.users_id = $%d",
-		tableConstants.ARTICLES_TABLE, setQuery, argId, argId+1)
+	query = fmt.Sprintf("UPDATE %s tl SET %s WHERE tl.uuid = $%d",
+		tableConstants.ARTICLES_TABLE, setQuery, argId)
 
 	args = append(args, article.Uuid)
-	args = append(args, usersId)
 
 	// Обновления данных о статье
-	_, err = r.db.Exec(query, args...)
+	_, err = tx.Exec(query, args...)
 	if err != nil {
 		tx.Rollback()
 		return false, err
 	}
 
+	// Обновление связей статьи с тегами
+	if err := setArticleTags(tx.Tx, article.Id, tags); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
 	// Добавление информации о новых изображениях
 	query = fmt.Sprintf("INSERT INTO %s (filename, filepath) values ($1, $2) RETURNING id", tableConstants.FILES_TABLE)
 	var filesId []articleModel.FileArticleExModel
@@ -256,14 +308,14 @@ func (r *UserPostgres) UpdateArticle(c *gin.Context, data articleModel.ArticleUp
 	// Запросы на удаление
 	query = fmt.Sprintf(`SELECT * FROM %s tl WHERE tl.index=$1 AND tl.articles_id=$2 LIMIT 1`, tableConstants.ARTICLES_FILES_TABLE)
 	queryDelete := fmt.Sprintf(`DELETE FROM %s tl WHERE tl.index=$1 AND tl.files_id=$2`, tableConstants.ARTICLES_FILES_TABLE)
-	queryDeleteFiles := fmt.Sprintf(`DELETE FROM %s tl WHERE tl.id=$1 RETURNING filepath`, tableConstants.FILES_TABLE)
+	queryDeleteFiles := fmt.Sprintf(`DELETE FROM %s tl WHERE tl.id=$1`, tableConstants.FILES_TABLE)
 
-	// Удаление старых файлов
+	// Удаление старых файлов из статьи (файлы на диске сохраняются для ревизий статьи)
 	if data.FilesDelete != nil {
 		for _, element := range *data.FilesDelete {
 			var articleFile []articleModel.ArticlesFilesModel
 
-			err := r.db.Select(&articleFile, query, element, article.Id)
+			err := tx.Select(&articleFile, query, element, article.Id)
 			if err != nil {
 				tx.Rollback()
 				return false, err
@@ -279,17 +331,21 @@ func (r *UserPostgres) UpdateArticle(c *gin.Context, data articleModel.ArticleUp
 				return false, err
 			}
 
-			var filePath string
-			row := tx.QueryRow(queryDeleteFiles, articleFile[0].FilesId)
-			if err := row.Scan(&filePath); err != nil {
+			_, err = tx.Exec(queryDeleteFiles, articleFile[0].FilesId)
+			if err != nil {
 				tx.Rollback()
 				return false, err
 			}
-
-			os.Remove(filePath)
 		}
 	}
 
+	// Сохранение новой ревизии статьи
+	err = saveArticleRevision(tx.Tx, article.Id, usersId)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		tx.Rollback()
@@ -301,17 +357,15 @@ func (r *UserPostgres) UpdateArticle(c *gin.Context, data articleModel.ArticleUp
 
 /* Получение информации о статье */
 func (r *UserPostgres) GetArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleModel, error) {
-	usersId, _ := c.Get(middlewareConstants.USER_CTX)
-
 	var article articleModel.ArticleDBModel
 
-	query := fmt.Sprintf("SELECT * FROM %s WHERE %s.uuid = $1 AND %s.users_id=$2 LIMIT 1",
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s.uuid = $1 AND %s.deleted_at IS NULL LIMIT 1",
 		tableConstants.ARTICLES_TABLE,
 		tableConstants.ARTICLES_TABLE,
 		tableConstants.ARTICLES_TABLE,
 	)
 
-	err := r.db.Get(&article, query, uuid.Uuid, usersId)
+	err := r.db.Get(&article, query, uuid.Uuid)
 	if err != nil {
 		return articleModel.ArticleModel{}, err
 	}
@@ -329,6 +383,17 @@ func (r *UserPostgres) GetArticle(uuid articleModel.ArticleUuidModel, c *gin.Con
 		return articleModel.ArticleModel{}, err
 	}
 
+	// Получение последнего решения модератора (например, причины отклонения статьи)
+	checks, err := getArticleChecks(r.db, article.Id)
+	if err != nil {
+		return articleModel.ArticleModel{}, err
+	}
+
+	var lastCheck *articleModel.ArticleCheckedModel = nil
+	if len(checks) > 0 {
+		lastCheck = &checks[0]
+	}
+
 	return articleModel.ArticleModel{
 		Uuid:      article.Uuid,
 		Filepath:  article.Filepath,
@@ -336,80 +401,301 @@ func (r *UserPostgres) GetArticle(uuid articleModel.ArticleUuidModel, c *gin.Con
 		Text:      article.Text,
 		Tags:      article.Tags,
 		Files:     articlesFiles,
+		Status:    article.Status,
+		PublishAt: article.PublishAt,
 		CreatedAt: article.CreatedAt,
 		UpdatedAt: article.UpdatedAt,
+		LastCheck: lastCheck,
 	}, nil
 }
 
-func (r *UserPostgres) GetArticles(c *gin.Context) (articleModel.ArticlesModel, error) {
+/* Получение страницы статей пользователя (включая статьи, которыми с ним поделились) */
+func (r *UserPostgres) GetArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error) {
 	usersId, _ := c.Get(middlewareConstants.USER_CTX)
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
 
-	query := fmt.Sprintf("SELECT * FROM %s WHERE users_id = $1", tableConstants.ARTICLES_TABLE)
+	shared := r.getSharedArticles(strconv.Itoa(usersId.(int)), strconv.Itoa(domainsId.(int)))
 
-	var articlesDb []articleModel.ArticleDBModel
-	err := r.db.Select(&articlesDb, query, usersId)
+	sharedUuids := make([]string, 0, len(shared))
+	for articleUuid := range shared {
+		sharedUuids = append(sharedUuids, articleUuid)
+	}
 
+	articles, err := getArticlesPage(r.db,
+		[]string{"(a1.users_id = $1 OR a1.uuid::text = ANY($2))", activeArticleCondition},
+		[]interface{}{usersId, pq.Array(sharedUuids)},
+		filter,
+	)
 	if err != nil {
 		return articleModel.ArticlesModel{}, err
 	}
 
-	var articles articleModel.ArticlesModel
+	for index, element := range articles.Articles {
+		articles.Articles[index].Access = shared[element.Uuid]
+	}
 
-	query = fmt.Sprintf(`SELECT index, filename, filepath FROM %s JOIN %s ON %s.files_id = %s.id WHERE %s.articles_id=$1;`,
-		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
-		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
-		tableConstants.ARTICLES_FILES_TABLE,
-	)
+	return articles, nil
+}
 
-	for _, element := range articlesDb {
-		var files []articleModel.ArticlesFilesDBModel
-		err := r.db.Select(&files, query, element.Id)
+/*
+* Получение статей, к которым пользователю предоставлен доступ другими авторами
+* (UUID статьи - уровень доступа; статьи, которые пользователь может удалять, принадлежат ему)
+ */
+func (r *UserPostgres) getSharedArticles(userId, domainId string) map[string]string {
+	access := make(map[string]string)
+	owned := make(map[string]bool)
+
+	for _, policy := range r.enforcer.GetFilteredPolicy(0, userId, domainId) {
+		object, action := policy[2], policy[3]
+
+		switch action {
+		case actionConstant.DELETE:
+			owned[object] = true
+		case actionConstant.MODIFY:
+			access[object] = actionConstant.MODIFY
+		case actionConstant.READ:
+			if access[object] == "" {
+				access[object] = actionConstant.READ
+			}
+		}
+	}
 
-		if err != nil {
-			return articleModel.ArticlesModel{}, err
+	for object := range owned {
+		delete(access, object)
+	}
+
+	return access
+}
+
+/* Предоставление пользователю доступа к статье (только автором статьи) */
+func (r *UserPostgres) GrantArticleAccess(data articleModel.ArticleGrantRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	article, grantee, err := r.getShareParticipants(data.Uuid, data.Email, c)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	// Право на изменение статьи включает право на её чтение
+	var actions []string
+
+	switch data.Access {
+	case actionConstant.READ:
+		actions = []string{actionConstant.READ}
+	case actionConstant.MODIFY:
+		actions = []string{actionConstant.READ, actionConstant.MODIFY}
+	default:
+		return articleModel.ArticleSuccessModel{}, errors.New("Некорректный уровень доступа к статье!")
+	}
+
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
+
+	var userId string = strconv.Itoa(grantee.Id)
+	var domainId string = strconv.Itoa(domainsId.(int))
+
+	policies := make([][]string, 0, len(actions))
+	for _, action := range actions {
+		policies = append(policies, []string{userId, domainId, article.Uuid, action})
+	}
+
+	// Замена ранее предоставленного доступа (при ошибке добавления прежний доступ восстанавливается)
+	previous := r.enforcer.GetFilteredPolicy(0, userId, domainId, article.Uuid)
+
+	_, err = r.enforcer.RemoveFilteredPolicy(0, userId, domainId, article.Uuid)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	_, err = r.enforcer.AddPolicies(policies)
+	if err != nil {
+		if len(previous) > 0 {
+			if _, restoreErr := r.enforcer.AddPolicies(previous); restoreErr != nil {
+				logrus.Errorf("failed to restore access of user %s to article %s: %s", userId, article.Uuid, restoreErr.Error())
+			}
 		}
 
-		articles.Articles = append(articles.Articles, articleModel.ArticleModel{
-			Uuid:      element.Uuid,
-			Filepath:  element.Filepath,
-			Title:     element.Title,
-			Text:      element.Text,
-			Tags:      element.Tags,
-			Files:     files,
-			CreatedAt: element.CreatedAt,
-			UpdatedAt: element.UpdatedAt,
-		})
+		return articleModel.ArticleSuccessModel{}, err
 	}
 
-	return articles, nil
+	return articleModel.ArticleSuccessModel{
+		Success: true,
+	}, nil
 }
 
-/* Удаление статьи */
-func (r *UserPostgres) DeleteArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+/* Отзыв доступа пользователя к статье (только автором статьи) */
+func (r *UserPostgres) RevokeArticleAccess(data articleModel.ArticleRevokeRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	article, grantee, err := r.getShareParticipants(data.Uuid, data.Email, c)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
+
+	removed, err := r.enforcer.RemoveFilteredPolicy(0, strconv.Itoa(grantee.Id), strconv.Itoa(domainsId.(int)), article.Uuid)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	if !removed {
+		return articleModel.ArticleSuccessModel{}, errShareParticipant
+	}
+
+	return articleModel.ArticleSuccessModel{
+		Success: true,
+	}, nil
+}
+
+/* Получение списка пользователей, которым автор предоставил доступ к статье */
+func (r *UserPostgres) GetArticleGrants(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleGrantsModel, error) {
 	usersId, _ := c.Get(middlewareConstants.USER_CTX)
+	domainsId, _ := c.Get(middlewareConstants.DOMAINS_ID)
 
-	var article articleModel.ArticleDBModel
+	article, err := r.getActiveArticle(uuid.Uuid)
+	if err != nil {
+		return articleModel.ArticleGrantsModel{}, err
+	}
 
-	query := fmt.Sprintf("SELECT * FROM %s WHERE %s.uuid = $1 AND %s.users_id=$2 LIMIT 1",
-		tableConstants.ARTICLES_TABLE,
-		tableConstants.ARTICLES_TABLE,
-		tableConstants.ARTICLES_TABLE,
+	if article.UsersId != usersId.(int) {
+		return articleModel.ArticleGrantsModel{}, errors.New("Управлять доступом к статье может только её автор!")
+	}
+
+	access := make(map[int]string)
+	for _, policy := range r.enforcer.GetFilteredPolicy(1, strconv.Itoa(domainsId.(int)), article.Uuid) {
+		granteeId, err := strconv.Atoi(policy[0])
+		if err != nil || granteeId == article.UsersId {
+			continue
+		}
+
+		if policy[3] == actionConstant.MODIFY || access[granteeId] == "" {
+			access[granteeId] = policy[3]
+		}
+	}
+
+	grants := articleModel.ArticleGrantsModel{
+		Uuid:   article.Uuid,
+		Grants: []articleModel.ArticleGrantModel{},
+	}
+
+	if len(access) <= 0 {
+		return grants, nil
+	}
+
+	ids := make([]int, 0, len(access))
+	for granteeId := range access {
+		ids = append(ids, granteeId)
+	}
+
+	query := fmt.Sprintf(`SELECT u.id, u.uuid, u.email, ud.data->>'nickname' AS nickname FROM %s u
+		LEFT JOIN %s ud ON ud.users_id = u.id WHERE u.id = ANY($1) ORDER BY u.email`,
+		tableConstants.USERS_TABLE, tableConstants.USERS_DATA_TABLE,
 	)
 
-	err := r.db.Get(&article, query, uuid.Uuid, usersId)
+	var users []articleModel.ArticleGrantDBModel
+
+	err = r.db.Select(&users, query, pq.Array(ids))
 	if err != nil {
-		return articleModel.ArticleSuccessModel{}, err
+		return articleModel.ArticleGrantsModel{}, err
 	}
 
-	var articlesFiles []articleModel.ArticlesFilesDBModel
+	for _, element := range users {
+		grants.Grants = append(grants.Grants, articleModel.ArticleGrantModel{
+			UserUuid: element.Uuid,
+			Email:    element.Email,
+			Nickname: element.Nickname,
+			Access:   access[element.Id],
+		})
+	}
 
-	query = fmt.Sprintf(`SELECT files_id, index, filename, filepath FROM %s JOIN %s ON %s.files_id = %s.id WHERE %s.articles_id=$1;`,
-		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
-		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
-		tableConstants.ARTICLES_FILES_TABLE,
-	)
+	return grants, nil
+}
 
-	err = r.db.Select(&articlesFiles, query, article.Id)
+/* Общая ошибка при некорректном пользователе, с которым автор делится статьёй */
+var errShareParticipant = errors.New("Не удалось изменить доступ данного пользователя к статье!")
+
+/* Получение статьи текущего пользователя и пользователя, с которым автор делится статьёй */
+func (r *UserPostgres) getShareParticipants(articleUuid, email string, c *gin.Context) (articleModel.ArticleDBModel, userModel.UserModel, error) {
+	usersId, _ := c.Get(middlewareConstants.USER_CTX)
+
+	article, err := r.getActiveArticle(articleUuid)
+	if err != nil {
+		return articleModel.ArticleDBModel{}, userModel.UserModel{}, err
+	}
+
+	if article.UsersId != usersId.(int) {
+		return articleModel.ArticleDBModel{}, userModel.UserModel{}, errors.New("Управлять доступом к статье может только её автор!")
+	}
+
+	// Ошибка не позволяет определить, зарегистрирован ли пользователь с данным email-адресом
+	grantee, err := r.GetUser("email", email)
+	if err != nil {
+		return articleModel.ArticleDBModel{}, userModel.UserModel{}, errShareParticipant
+	}
+
+	if grantee.Id == article.UsersId {
+		return articleModel.ArticleDBModel{}, userModel.UserModel{}, errors.New("Автор статьи уже имеет к ней полный доступ!")
+	}
+
+	return article, grantee, nil
+}
+
+/* Полнотекстовый поиск по статьям пользователя */
+func (r *UserPostgres) SearchArticles(c *gin.Context, search articleModel.ArticlesSearchRequestModel) (articleModel.ArticlesSearchModel, error) {
+	usersId, _ := c.Get(middlewareConstants.USER_CTX)
+
+	return searchArticles(r.db, []string{"a1.users_id = $1", activeArticleCondition}, []interface{}{usersId}, search)
+}
+
+/* Получение списка ревизий статьи */
+func (r *UserPostgres) GetArticleRevisions(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleRevisionsModel, error) {
+	article, err := r.getActiveArticle(uuid.Uuid)
+	if err != nil {
+		return articleModel.ArticleRevisionsModel{}, err
+	}
+
+	revisions, err := getArticleRevisions(r.db, article.Id)
+	if err != nil {
+		return articleModel.ArticleRevisionsModel{}, err
+	}
+
+	return articleModel.ArticleRevisionsModel{
+		Uuid:      article.Uuid,
+		Revisions: revisions,
+	}, nil
+}
+
+/* Сравнение двух ревизий статьи */
+func (r *UserPostgres) GetArticleRevisionsDiff(data articleModel.ArticleRevisionDiffRequestModel, c *gin.Context) (articleModel.ArticleRevisionDiffModel, error) {
+	article, err := r.getActiveArticle(data.Uuid)
+	if err != nil {
+		return articleModel.ArticleRevisionDiffModel{}, err
+	}
+
+	from, err := getArticleRevision(r.db, article.Id, data.From)
+	if err != nil {
+		return articleModel.ArticleRevisionDiffModel{}, errors.New("Ревизии статьи не существует!")
+	}
+
+	to, err := getArticleRevision(r.db, article.Id, data.To)
+	if err != nil {
+		return articleModel.ArticleRevisionDiffModel{}, errors.New("Ревизии статьи не существует!")
+	}
+
+	return diffArticleRevisions(article.Uuid, from, to), nil
+}
+
+/* Восстановление статьи из ревизии (восстановленное состояние сохраняется как новая ревизия) */
+func (r *UserPostgres) RestoreArticleRevision(data articleModel.ArticleRevisionRestoreRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	usersId, _ := c.Get(middlewareConstants.USER_CTX)
+
+	article, err := r.getActiveArticle(data.Uuid)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	revision, err := getArticleRevision(r.db, article.Id, data.Version)
+	if err != nil || data.Version == 0 {
+		return articleModel.ArticleSuccessModel{}, errors.New("Ревизии статьи не существует!")
+	}
+
+	tags, err := normalizeTags(revision.Tags)
 	if err != nil {
 		return articleModel.ArticleSuccessModel{}, err
 	}
@@ -419,49 +705,178 @@ func (r *UserPostgres) DeleteArticle(uuid articleModel.ArticleUuidModel, c *gin.
 		return articleModel.ArticleSuccessModel{}, err
 	}
 
-	query = fmt.Sprintf(`DELETE FROM %s tl WHERE tl.files_id=$1`, tableConstants.ARTICLES_FILES_TABLE)
-	queryFiles := fmt.Sprintf(`DELETE FROM %s tl WHERE tl.id=$1`, tableConstants.FILES_TABLE)
+	// Восстановленная опубликованная статья снова отправляется на проверку
+	status := article.Status
+	if status == articleConstant.STATUS_PUBLISHED {
+		status = articleConstant.STATUS_REVIEW
+	}
 
-	// Удаление файлов
-	for _, element := range articlesFiles {
-		_, err = r.db.Query(query, element.FilesId)
-		if err != nil {
-			tx.Rollback()
-			return articleModel.ArticleSuccessModel{}, err
-		}
+	// Восстановление содержимого статьи
+	query := fmt.Sprintf("UPDATE %s tl SET title=$1, text=$2, tags=$3, filename=$4, filepath=$5, updated_at=$6, status=$7 WHERE tl.id = $8",
+		tableConstants.ARTICLES_TABLE,
+	)
 
-		_, err = r.db.Query(queryFiles, element.FilesId)
-		if err != nil {
+	_, err = tx.Exec(query, revision.Title, revision.Text, strings.Join(tags, constant.SEPARATOR), revision.Filename, revision.Filepath, time.Now(), status, article.Id)
+	if err != nil {
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	if err := setArticleTags(tx, article.Id, tags); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	// Замена текущего набора файлов статьи на набор файлов из ревизии
+	query = fmt.Sprintf(`WITH deleted AS (DELETE FROM %s tl WHERE tl.articles_id = $1 RETURNING tl.files_id)
+		DELETE FROM %s tl WHERE tl.id IN (SELECT files_id FROM deleted)`,
+		tableConstants.ARTICLES_FILES_TABLE, tableConstants.FILES_TABLE,
+	)
+
+	_, err = tx.Exec(query, article.Id)
+	if err != nil {
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (filename, filepath) values ($1, $2) RETURNING id", tableConstants.FILES_TABLE)
+	queryLink := fmt.Sprintf("INSERT INTO %s (articles_id, files_id, index) values ($1, $2, $3)", tableConstants.ARTICLES_FILES_TABLE)
+
+	for _, element := range revision.Files {
+		var fileId int
+		row := tx.QueryRow(query, element.Filename, element.Filepath)
+		if err := row.Scan(&fileId); err != nil {
 			tx.Rollback()
 			return articleModel.ArticleSuccessModel{}, err
 		}
 
-		err = os.Remove(element.Filepath)
+		_, err = tx.Exec(queryLink, article.Id, fileId, element.Index)
 		if err != nil {
 			tx.Rollback()
 			return articleModel.ArticleSuccessModel{}, err
 		}
 	}
 
-	err = os.Remove(article.Filepath)
+	err = saveArticleRevision(tx, article.Id, usersId)
 	if err != nil {
 		tx.Rollback()
 		return articleModel.ArticleSuccessModel{}, err
 	}
 
-	query = fmt.Sprintf(`DELETE FROM %s tl WHERE tl.uuid=$1`, tableConstants.ARTICLES_TABLE)
-	_, err = r.db.Query(query, article.Uuid)
+	err = tx.Commit()
 	if err != nil {
 		tx.Rollback()
 		return articleModel.ArticleSuccessModel{}, err
 	}
 
-	err = tx.Commit()
+	return articleModel.ArticleSuccessModel{
+		Success: true,
+	}, nil
+}
+
+/*
+* Изменение статуса статьи автором (публикация статьи выполняется только после одобрения модератором)
+ */
+func (r *UserPostgres) UpdateArticleStatus(data articleModel.ArticleStatusRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	article, err := r.getActiveArticle(data.Uuid)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	switch data.Status {
+	case articleConstant.STATUS_DRAFT, articleConstant.STATUS_ARCHIVED:
+		break
+	case articleConstant.STATUS_REVIEW:
+		if article.Status != articleConstant.STATUS_DRAFT && article.Status != articleConstant.STATUS_REVIEW {
+			return articleModel.ArticleSuccessModel{}, errors.New("На проверку может быть отправлен только черновик статьи!")
+		}
+		break
+	default:
+		return articleModel.ArticleSuccessModel{}, errors.New("Некорректный статус статьи!")
+	}
+
+	updatedAt := article.UpdatedAt
+
+	// Отправленный на проверку черновик попадает в очередь модератора
+	if article.Status == articleConstant.STATUS_DRAFT && data.Status == articleConstant.STATUS_REVIEW {
+		updatedAt = time.Now()
+	}
+
+	query := fmt.Sprintf("UPDATE %s tl SET status=$1, publish_at=$2, updated_at=$3 WHERE tl.id = $4",
+		tableConstants.ARTICLES_TABLE,
+	)
+
+	_, err = r.db.Exec(query, data.Status, data.PublishAt, updatedAt, article.Id)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	return articleModel.ArticleSuccessModel{
+		Success: true,
+	}, nil
+}
+
+/*
+* Получение статьи, не находящейся в корзине
+* (права пользователя на статью проверяются по политикам доступа в middleware articleEnforce)
+ */
+func (r *UserPostgres) getActiveArticle(articleUuid string) (articleModel.ArticleDBModel, error) {
+	var article articleModel.ArticleDBModel
+
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.uuid = $1 AND tl.deleted_at IS NULL LIMIT 1", tableConstants.ARTICLES_TABLE)
+
+	err := r.db.Get(&article, query, articleUuid)
+
+	return article, err
+}
+
+/* Перемещение статьи в корзину (файлы статьи удаляются при очистке корзины) */
+func (r *UserPostgres) DeleteArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	article, err := r.getActiveArticle(uuid.Uuid)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s tl SET deleted_at=$1 WHERE tl.id = $2", tableConstants.ARTICLES_TABLE)
+
+	_, err = r.db.Exec(query, time.Now(), article.Id)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	return articleModel.ArticleSuccessModel{
+		Success: true,
+	}, nil
+}
+
+/* Получение страницы статей пользователя, находящихся в корзине */
+func (r *UserPostgres) GetTrashArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error) {
+	usersId, _ := c.Get(middlewareConstants.USER_CTX)
+
+	return getArticlesPage(r.db, []string{"a1.users_id = $1", deletedArticleCondition}, []interface{}{usersId}, filter)
+}
+
+/* Восстановление статьи из корзины */
+func (r *UserPostgres) RestoreArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	query := fmt.Sprintf("UPDATE %s AS a1 SET deleted_at=NULL WHERE a1.uuid = $1 AND %s",
+		tableConstants.ARTICLES_TABLE,
+		deletedArticleCondition,
+	)
+
+	result, err := r.db.Exec(query, uuid.Uuid)
 	if err != nil {
-		tx.Rollback()
 		return articleModel.ArticleSuccessModel{}, err
 	}
 
+	count, err := result.RowsAffected()
+	if err != nil {
+		return articleModel.ArticleSuccessModel{}, err
+	}
+
+	if count <= 0 {
+		return articleModel.ArticleSuccessModel{}, errors.New("Статья не найдена в корзине!")
+	}
+
 	return articleModel.ArticleSuccessModel{
 		Success: true,
 	}, nil
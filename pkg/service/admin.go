@@ -0,0 +1,91 @@
+package service
+
+import (
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* Structure for this service */
+type AdminService struct {
+	repo repository.Admin
+}
+
+/* Function for create new service */
+func NewAdminService(repo repository.Admin) *AdminService {
+	return &AdminService{
+		repo: repo,
+	}
+}
+
+/* Create new domain */
+func (s *AdminService) CreateDomain(c *gin.Context, data rbacModel.DomainCreateRequestModel) (rbacModel.DomainModel, error) {
+	return s.repo.CreateDomain(c, data)
+}
+
+/* Update domain */
+func (s *AdminService) UpdateDomain(c *gin.Context, data rbacModel.DomainUpdateRequestModel) (rbacModel.DomainModel, error) {
+	return s.repo.UpdateDomain(c, data)
+}
+
+/* Delete domain */
+func (s *AdminService) DeleteDomain(c *gin.Context, data rbacModel.DomainUuidModel) (rbacModel.RbacSuccessModel, error) {
+	return s.repo.DeleteDomain(c, data)
+}
+
+/* Get domain */
+func (s *AdminService) GetDomain(c *gin.Context, data rbacModel.DomainUuidModel) (rbacModel.DomainModel, error) {
+	return s.repo.GetDomain(c, data)
+}
+
+/* Get all domains */
+func (s *AdminService) GetDomains(c *gin.Context) (rbacModel.DomainsModel, error) {
+	return s.repo.GetDomains(c)
+}
+
+/* Create new role in domain */
+func (s *AdminService) CreateRole(c *gin.Context, data rbacModel.RoleCreateRequestModel) (rbacModel.RoleModel, error) {
+	return s.repo.CreateRole(c, data)
+}
+
+/* Update role */
+func (s *AdminService) UpdateRole(c *gin.Context, data rbacModel.RoleUpdateRequestModel) (rbacModel.RoleModel, error) {
+	return s.repo.UpdateRole(c, data)
+}
+
+/* Delete role */
+func (s *AdminService) DeleteRole(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RbacSuccessModel, error) {
+	return s.repo.DeleteRole(c, data)
+}
+
+/* Get role */
+func (s *AdminService) GetRole(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RoleModel, error) {
+	return s.repo.GetRole(c, data)
+}
+
+/* Get all roles (or roles of domain) */
+func (s *AdminService) GetRoles(c *gin.Context, data rbacModel.RolesRequestModel) (rbacModel.RolesModel, error) {
+	return s.repo.GetRoles(c, data)
+}
+
+/* Assign role to user */
+func (s *AdminService) AssignRole(c *gin.Context, data rbacModel.RoleAssignRequestModel) (rbacModel.RbacSuccessModel, error) {
+	return s.repo.AssignRole(c, data)
+}
+
+/* Remove role from user */
+func (s *AdminService) RemoveRole(c *gin.Context, data rbacModel.RoleAssignRequestModel) (rbacModel.RbacSuccessModel, error) {
+	return s.repo.RemoveRole(c, data)
+}
+
+/* Get users who have role */
+func (s *AdminService) GetRoleUsers(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RoleUsersModel, error) {
+	return s.repo.GetRoleUsers(c, data)
+}
+
+/* Force logout of user (all sessions and access tokens are revoked) */
+func (s *AdminService) ForceLogout(c *gin.Context, data rbacModel.UserUuidModel) (userModel.SessionRevokeModel, error) {
+	return s.repo.ForceLogout(c, data)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	rbacModel "main-server/pkg/model/rbac"
 	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
 
@@ -23,29 +24,50 @@ func NewAuthService(repo repository.Authorization, tokenService TokenService) *A
 }
 
 /* Create user */
-func (s *AuthService) CreateUser(user userModel.UserRegisterModel) (userModel.UserAuthDataModel, error) {
-	return s.repo.CreateUser(user)
+func (s *AuthService) CreateUser(user userModel.UserRegisterModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
+	return s.repo.CreateUser(user, domain, session)
 }
 
 /* Login user */
-func (s *AuthService) LoginUser(user userModel.UserLoginModel) (userModel.UserAuthDataModel, error) {
-	return s.repo.LoginUser(user)
+func (s *AuthService) LoginUser(user userModel.UserLoginModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
+	return s.repo.LoginUser(user, domain, session)
 }
 
-/* Login user with Google OAuth2 */
-func (s *AuthService) LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error) {
-	return s.repo.LoginUserOAuth2(code)
+/* Login user with external provider (Google, VK, OpenID Connect) */
+func (s *AuthService) LoginUserProvider(provider, code string, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
+	return s.repo.LoginUserProvider(provider, code, domain, session)
+}
+
+/* Login user with second factor */
+func (s *AuthService) LoginUserMfa(data userModel.UserMfaLoginModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
+	token, err := s.tokenService.ParseMfaToken(data.MfaToken, viper.GetString("token.signing_key_mfa"))
+
+	if err != nil {
+		return userModel.UserAuthDataModel{}, errors.New("Некорректный токен двухфакторной аутентификации")
+	}
+
+	return s.repo.LoginUserMfa(data, token, domain, session)
 }
 
 /* Refresh tokens for user */
-func (s *AuthService) Refresh(data userModel.TokenLogoutDataModel, refreshToken string) (userModel.UserAuthDataModel, error) {
+func (s *AuthService) Refresh(data userModel.TokenLogoutDataModel, refreshToken string, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error) {
 	token, err := s.tokenService.ParseTokenWithoutValid(refreshToken, viper.GetString("token.signing_key_refresh"))
 
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	return s.repo.Refresh(data, refreshToken, token)
+	// Token of refresh can be used only in the domain for which it was issued
+	if token.DomainUuid != domain.Uuid {
+		return userModel.UserAuthDataModel{}, errors.New("Токен обновления выдан для другого домена!")
+	}
+
+	// Refresh token and access token must belong to the same session
+	if token.SessionUuid != data.SessionUuid {
+		return userModel.UserAuthDataModel{}, errors.New("Токен обновления принадлежит другой сессии!")
+	}
+
+	return s.repo.Refresh(data, refreshToken, token, session)
 }
 
 /* Logout user */
@@ -58,9 +80,24 @@ func (s *AuthService) Activate(link string) (bool, error) {
 	return s.repo.Activate(link)
 }
 
+/* Resend link for activation of account */
+func (s *AuthService) ResendActivation(email string, session userModel.SessionDataModel) (bool, error) {
+	return s.repo.ResendActivation(email, session)
+}
+
+/* Check that account of user is activated */
+func (s *AuthService) IsActivated(usersId int) (bool, error) {
+	return s.repo.IsActivated(usersId)
+}
+
+/* Unlock account of user, which is locked after failed attempts of sign-in */
+func (s *AuthService) UnlockAccount(link string) (bool, error) {
+	return s.repo.UnlockAccount(link)
+}
+
 /* Recover password */
-func (s *AuthService) RecoveryPassword(email string) (bool, error) {
-	return s.repo.RecoveryPassword(email)
+func (s *AuthService) RecoveryPassword(email string, session userModel.SessionDataModel) (bool, error) {
+	return s.repo.RecoveryPassword(email, session)
 }
 
 /* Reset password */
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+/* Prefix of encrypted value (version of encryption format) */
+const tokenCipherPrefix = "v1:"
+
+var ErrTokenCipherDisabled = errors.New("encryption key of external tokens is not configured")
+
+var (
+	tokenCipherMutex sync.RWMutex
+	tokenCipher      cipher.AEAD
+)
+
+/*
+* Initialization of encryption of external tokens at rest (AES-256-GCM)
+* (key is derived from configured secret, empty secret disables storing of tokens)
+ */
+func InitTokenCipher(secret string) error {
+	tokenCipherMutex.Lock()
+	defer tokenCipherMutex.Unlock()
+
+	if secret == "" {
+		tokenCipher = nil
+		return ErrTokenCipherDisabled
+	}
+
+	key := sha256.Sum256([]byte(secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	tokenCipher = aead
+
+	return nil
+}
+
+func getTokenCipher() (cipher.AEAD, error) {
+	tokenCipherMutex.RLock()
+	defer tokenCipherMutex.RUnlock()
+
+	if tokenCipher == nil {
+		return nil, ErrTokenCipherDisabled
+	}
+
+	return tokenCipher, nil
+}
+
+/* Encryption of external token */
+func EncryptToken(token string) (string, error) {
+	aead, err := getTokenCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(token), nil)
+
+	return tokenCipherPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+/* Checking that value is encrypted (values stored before encryption are kept as is) */
+func IsEncryptedToken(value string) bool {
+	return strings.HasPrefix(value, tokenCipherPrefix)
+}
+
+/* Decryption of external token */
+func DecryptToken(value string) (string, error) {
+	aead, err := getTokenCipher()
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(value, tokenCipherPrefix) {
+		return "", errors.New("unknown format of encrypted token")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, tokenCipherPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("encrypted token is too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
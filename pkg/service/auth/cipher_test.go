@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func initTestTokenCipher(t *testing.T, secret string) {
+	if err := InitTokenCipher(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { InitTokenCipher("") })
+}
+
+/* Encrypted token is decrypted back, every encryption uses its own nonce */
+func TestTokenCipherRoundTrip(t *testing.T) {
+	initTestTokenCipher(t, "identity-key")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "access token", token: "ya29.a0AfH6SMBx-example-token"},
+		{name: "unicode", token: "токен провайдера"},
+		{name: "long", token: strings.Repeat("t", 4096)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := EncryptToken(tt.token)
+			if err != nil {
+				t.Fatalf("EncryptToken() error = %v", err)
+			}
+
+			if !IsEncryptedToken(encrypted) {
+				t.Errorf("IsEncryptedToken(%q) = false", encrypted)
+			}
+
+			if tt.token != "" && strings.Contains(encrypted, tt.token) {
+				t.Errorf("EncryptToken() keeps token in plain text")
+			}
+
+			again, err := EncryptToken(tt.token)
+			if err != nil {
+				t.Fatalf("EncryptToken() error = %v", err)
+			}
+
+			if again == encrypted {
+				t.Errorf("EncryptToken() returns the same value twice")
+			}
+
+			decrypted, err := DecryptToken(encrypted)
+			if err != nil {
+				t.Fatalf("DecryptToken() error = %v", err)
+			}
+
+			if decrypted != tt.token {
+				t.Errorf("DecryptToken() = %q, want %q", decrypted, tt.token)
+			}
+		})
+	}
+}
+
+/* Modified, truncated or foreign values are not decrypted */
+func TestDecryptTokenTampered(t *testing.T) {
+	initTestTokenCipher(t, "identity-key")
+
+	encrypted, err := EncryptToken("provider-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, tokenCipherPrefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flip := func(index int) string {
+		data := append([]byte(nil), sealed...)
+		data[index] ^= 0x01
+
+		return tokenCipherPrefix + base64.StdEncoding.EncodeToString(data)
+	}
+
+	if err := InitTokenCipher("another-key"); err != nil {
+		t.Fatal(err)
+	}
+
+	foreign, err := EncryptToken("provider-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initTestTokenCipher(t, "identity-key")
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "plain value", value: "provider-token"},
+		{name: "unknown version", value: "v2:" + strings.TrimPrefix(encrypted, tokenCipherPrefix)},
+		{name: "not base64", value: tokenCipherPrefix + "!!!"},
+		{name: "shorter than nonce", value: tokenCipherPrefix + base64.StdEncoding.EncodeToString(sealed[:4])},
+		{name: "truncated", value: tokenCipherPrefix + base64.StdEncoding.EncodeToString(sealed[:len(sealed)-1])},
+		{name: "modified nonce", value: flip(0)},
+		{name: "modified ciphertext", value: flip(len(sealed) / 2)},
+		{name: "modified tag", value: flip(len(sealed) - 1)},
+		{name: "encrypted by another key", value: foreign},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if decrypted, err := DecryptToken(tt.value); err == nil {
+				t.Errorf("DecryptToken(%q) = %q, want error", tt.value, decrypted)
+			}
+		})
+	}
+}
+
+/* Without key tokens are neither encrypted nor decrypted */
+func TestTokenCipherDisabled(t *testing.T) {
+	initTestTokenCipher(t, "identity-key")
+
+	encrypted, err := EncryptToken("provider-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitTokenCipher(""); err != ErrTokenCipherDisabled {
+		t.Fatalf("InitTokenCipher(\"\") error = %v, want %v", err, ErrTokenCipherDisabled)
+	}
+
+	if _, err := EncryptToken("provider-token"); err != ErrTokenCipherDisabled {
+		t.Errorf("EncryptToken() error = %v, want %v", err, ErrTokenCipherDisabled)
+	}
+
+	if _, err := DecryptToken(encrypted); err != ErrTokenCipherDisabled {
+		t.Errorf("DecryptToken() error = %v, want %v", err, ErrTokenCipherDisabled)
+	}
+}
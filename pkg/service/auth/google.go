@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	config "main-server/config"
+	route "main-server/pkg/constant/route"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+type VerifyEmailModel struct {
+	VerifyEmail bool `json:"verified_email" binding:"required"`
+}
+
+/* Model of user profile from Google (userinfo v2) */
+type googleUserInfoModel struct {
+	Id            string `json:"id"`
+	Email         string `json:"email"`
+	VerifiedEmail bool   `json:"verified_email"`
+	Name          string `json:"name"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+}
+
+/* Provider of authorization through Google OAuth2 */
+type GoogleProvider struct {
+	name   string
+	config oauth2.Config
+}
+
+func NewGoogleProvider(name string, cfg config.OAuth2ProviderConfig) *GoogleProvider {
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"https://www.googleapis.com/auth/userinfo.email", "https://www.googleapis.com/auth/userinfo.profile"}
+	}
+
+	return &GoogleProvider{
+		name: name,
+		config: oauth2.Config{
+			ClientID:     cfg.ClientId,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     google.Endpoint,
+			RedirectURL:  firstNotEmpty(cfg.RedirectUrl, "http://localhost:3000"),
+			Scopes:       scopes,
+		},
+	}
+}
+
+func (p *GoogleProvider) Name() string {
+	return p.name
+}
+
+func (p *GoogleProvider) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	return p.config.AuthCodeURL(state), nil
+}
+
+func (p *GoogleProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return p.config.Exchange(providerContext(ctx), code)
+}
+
+func (p *GoogleProvider) UserInfo(ctx context.Context, token *oauth2.Token) (userModel.ProviderUserModel, error) {
+	var data googleUserInfoModel
+	if err := getJSON(ctx, route.OAUTH2_USER_INFO_ROUTE+url.QueryEscape(token.AccessToken), &data); err != nil {
+		return userModel.ProviderUserModel{}, err
+	}
+
+	return userModel.ProviderUserModel{
+		Subject:       data.Id,
+		Email:         data.Email,
+		EmailVerified: data.VerifiedEmail,
+		GivenName:     data.GivenName,
+		FamilyName:    data.FamilyName,
+		Name:          data.Name,
+	}, nil
+}
+
+func (p *GoogleProvider) Verify(ctx context.Context, accessToken string) (bool, error) {
+	var data VerifyEmailModel
+	if err := getJSON(ctx, route.OAUTH2_TOKEN_INFO_ROUTE+url.QueryEscape(accessToken), &data); err != nil {
+		return false, err
+	}
+
+	return data.VerifyEmail, nil
+}
+
+func (p *GoogleProvider) Refresh(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	return refreshProviderToken(ctx, &p.config, refreshToken)
+}
+
+func (p *GoogleProvider) Revoke(ctx context.Context, token string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, route.OAUTH2_REVOKE_TOKEN_ROUTE+url.QueryEscape(token), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := providerClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return errors.New("Не удалось отозвать токен Google!")
+	}
+
+	return nil
+}
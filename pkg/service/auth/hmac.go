@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"fmt"
+	authConstants "main-server/pkg/constant/auth"
+)
+
+/*
+* Getting of secret key for signing of tokens by HMAC (refresh, password reset and second factor tokens)
+* (empty or short key is refused, otherwise such tokens can be forged)
+ */
+func HmacKey(signingKey string) ([]byte, error) {
+	if len(signingKey) < authConstants.HMAC_KEY_MIN_LENGTH {
+		return nil, fmt.Errorf("signing key must be at least %d bytes long", authConstants.HMAC_KEY_MIN_LENGTH)
+	}
+
+	return []byte(signingKey), nil
+}
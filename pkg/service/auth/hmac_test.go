@@ -0,0 +1,34 @@
+package auth
+
+import (
+	authConstants "main-server/pkg/constant/auth"
+	"strings"
+	"testing"
+)
+
+/* Empty and short keys of HMAC tokens are refused */
+func TestHmacKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "empty", key: "", wantErr: true},
+		{name: "short", key: strings.Repeat("k", authConstants.HMAC_KEY_MIN_LENGTH-1), wantErr: true},
+		{name: "minimum length", key: strings.Repeat("k", authConstants.HMAC_KEY_MIN_LENGTH)},
+		{name: "long", key: strings.Repeat("k", authConstants.HMAC_KEY_MIN_LENGTH*2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := HmacKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HmacKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && string(key) != tt.key {
+				t.Errorf("HmacKey() = %q, want %q", key, tt.key)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var ErrNoSigningKey = errors.New("no active key for signing of access tokens")
+
+/* Signing method of Ed25519 keys (RFC 8037), which is not implemented by jwt-go */
+type signingMethodEdDSA struct{}
+
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return errors.New("ed25519: verification error")
+	}
+
+	return nil
+}
+
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
+
+/* Key of keyring of access tokens */
+type signingKey struct {
+	kid        string
+	method     jwt.SigningMethod
+	privateKey crypto.Signer
+	activeFrom time.Time
+	expiresAt  time.Time
+}
+
+/* Key is accepted for verification and published in JWKS until it expires */
+func (k *signingKey) isValid(now time.Time) bool {
+	return k.expiresAt.IsZero() || now.Before(k.expiresAt)
+}
+
+/*
+* Key is used for signing since activation time, but stops signing beforehand of expiration,
+* so that tokens signed by key never outlive it
+ */
+func (k *signingKey) canSign(now time.Time) bool {
+	return !k.activeFrom.After(now) && k.isValid(now.Add(authConstants.TOKEN_TLL_ACCESS))
+}
+
+var (
+	keyringMutex sync.RWMutex
+	keyring      []*signingKey
+)
+
+/*
+* Initialization of keyring of access tokens
+* (several keys can be valid at the same time, the most recently activated key signs new tokens,
+* so rotation is scheduled by adding of new key with future activation time)
+ */
+func InitKeyring(configs []config.SigningKeyConfig) error {
+	keys := make([]*signingKey, 0, len(configs))
+	kids := make(map[string]bool)
+
+	for _, item := range configs {
+		if item.Kid == "" {
+			return fmt.Errorf("kid of signing key %s is not set", item.Path)
+		}
+
+		if kids[item.Kid] {
+			return fmt.Errorf("signing key %s is duplicated", item.Kid)
+		}
+
+		key, err := loadSigningKey(item)
+		if err != nil {
+			return err
+		}
+
+		kids[item.Kid] = true
+		keys = append(keys, key)
+	}
+
+	if selectSigningKey(keys, time.Now()) == nil {
+		return ErrNoSigningKey
+	}
+
+	keyringMutex.Lock()
+	defer keyringMutex.Unlock()
+
+	keyring = keys
+
+	return nil
+}
+
+/* Loading of private key in PEM format (PKCS #1 or PKCS #8) */
+func loadSigningKey(item config.SigningKeyConfig) (*signingKey, error) {
+	data, err := os.ReadFile(item.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s: PEM block is not found", item.Kid)
+	}
+
+	var parsed interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: %s", item.Kid, err.Error())
+	}
+
+	key := &signingKey{
+		kid: item.Kid,
+	}
+
+	switch value := parsed.(type) {
+	case *rsa.PrivateKey:
+		if value.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("signing key %s: RSA key must be at least 2048 bits", item.Kid)
+		}
+
+		key.method = jwt.SigningMethodRS256
+		key.privateKey = value
+
+	case ed25519.PrivateKey:
+		key.method = SigningMethodEdDSA
+		key.privateKey = value
+
+	default:
+		return nil, fmt.Errorf("signing key %s: only RSA and Ed25519 keys are supported", item.Kid)
+	}
+
+	if key.activeFrom, err = parseKeyTime(item.ActiveFrom); err != nil {
+		return nil, fmt.Errorf("signing key %s: %s", item.Kid, err.Error())
+	}
+
+	if key.expiresAt, err = parseKeyTime(item.ExpiresAt); err != nil {
+		return nil, fmt.Errorf("signing key %s: %s", item.Kid, err.Error())
+	}
+
+	return key, nil
+}
+
+func parseKeyTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+/* Selection of the most recently activated key, which can sign tokens */
+func selectSigningKey(keys []*signingKey, now time.Time) *signingKey {
+	var current *signingKey
+
+	for _, key := range keys {
+		if key.canSign(now) && (current == nil || key.activeFrom.After(current.activeFrom)) {
+			current = key
+		}
+	}
+
+	return current
+}
+
+/* Signing of access token by current key of keyring (kid of key is added to header of token) */
+func SignToken(claims jwt.Claims) (string, error) {
+	keyringMutex.RLock()
+	key := selectSigningKey(keyring, time.Now())
+	keyringMutex.RUnlock()
+
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	return token.SignedString(key.privateKey)
+}
+
+/* Getting of public key for verification of access token by kid from header of token (jwt.Keyfunc) */
+func VerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("kid of token is not set")
+	}
+
+	keyringMutex.RLock()
+	defer keyringMutex.RUnlock()
+
+	now := time.Now()
+	for _, key := range keyring {
+		if key.kid != kid || !key.isValid(now) {
+			continue
+		}
+
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("invalid signing method")
+		}
+
+		return key.privateKey.Public(), nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %s", kid)
+}
+
+/* Getting of public keys of keyring, which are not expired (JWKS) */
+func JWKS() userModel.JWKSModel {
+	keyringMutex.RLock()
+	defer keyringMutex.RUnlock()
+
+	result := userModel.JWKSModel{
+		Keys: make([]userModel.JWKModel, 0, len(keyring)),
+	}
+
+	now := time.Now()
+	for _, key := range keyring {
+		if !key.isValid(now) {
+			continue
+		}
+
+		jwk := userModel.JWKModel{
+			Use: "sig",
+			Alg: key.method.Alg(),
+			Kid: key.kid,
+		}
+
+		switch publicKey := key.privateKey.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+
+		result.Keys = append(result.Keys, jwk)
+	}
+
+	return result
+}
@@ -0,0 +1,266 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+/* Writing of private key in PEM format (PKCS #8) into temporary directory of test */
+func writeTestKey(t *testing.T, name string, key interface{}) string {
+	data, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), name+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: data}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func generateTestEd25519Key(t *testing.T) ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func generateTestRSAKey(t *testing.T, bits int) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func initTestKeyring(t *testing.T, configs []config.SigningKeyConfig) {
+	if err := InitKeyring(configs); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		keyringMutex.Lock()
+		keyring = nil
+		keyringMutex.Unlock()
+	})
+}
+
+/* The most recently activated key signs tokens, keys close to expiration do not sign */
+func TestSelectSigningKey(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	key := func(kid string, activeFrom, expiresAt time.Time) *signingKey {
+		return &signingKey{kid: kid, activeFrom: activeFrom, expiresAt: expiresAt}
+	}
+
+	tests := []struct {
+		name string
+		keys []*signingKey
+		want string
+	}{
+		{name: "no keys", keys: nil, want: ""},
+		{name: "single key without dates", keys: []*signingKey{key("a", time.Time{}, time.Time{})}, want: "a"},
+		{
+			name: "newest active key",
+			keys: []*signingKey{key("a", now.Add(-48*time.Hour), time.Time{}), key("b", now.Add(-time.Hour), time.Time{})},
+			want: "b",
+		},
+		{
+			name: "key is not active yet",
+			keys: []*signingKey{key("a", now.Add(-48*time.Hour), time.Time{}), key("b", now.Add(time.Hour), time.Time{})},
+			want: "a",
+		},
+		{
+			name: "key expires before token",
+			keys: []*signingKey{key("a", now.Add(-48*time.Hour), time.Time{}), key("b", now.Add(-time.Hour), now.Add(authConstants.TOKEN_TLL_ACCESS/2))},
+			want: "a",
+		},
+		{
+			name: "all keys expired",
+			keys: []*signingKey{key("a", time.Time{}, now.Add(-time.Hour)), key("b", time.Time{}, now)},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if key := selectSigningKey(tt.keys, now); key != nil {
+				got = key.kid
+			}
+
+			if got != tt.want {
+				t.Errorf("selectSigningKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+/* Invalid configuration of keyring is refused */
+func TestInitKeyringInvalid(t *testing.T) {
+	edPath := writeTestKey(t, "ed", generateTestEd25519Key(t))
+	weakPath := writeTestKey(t, "weak", generateTestRSAKey(t, 1024))
+
+	notPemPath := filepath.Join(t.TempDir(), "not-pem.pem")
+	if err := os.WriteFile(notPemPath, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	future := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name    string
+		configs []config.SigningKeyConfig
+	}{
+		{name: "no keys", configs: nil},
+		{name: "no kid", configs: []config.SigningKeyConfig{{Path: edPath}}},
+		{name: "duplicated kid", configs: []config.SigningKeyConfig{{Kid: "a", Path: edPath}, {Kid: "a", Path: edPath}}},
+		{name: "missing file", configs: []config.SigningKeyConfig{{Kid: "a", Path: filepath.Join(t.TempDir(), "missing.pem")}}},
+		{name: "not PEM", configs: []config.SigningKeyConfig{{Kid: "a", Path: notPemPath}}},
+		{name: "weak RSA key", configs: []config.SigningKeyConfig{{Kid: "a", Path: weakPath}}},
+		{name: "invalid activation time", configs: []config.SigningKeyConfig{{Kid: "a", Path: edPath, ActiveFrom: "tomorrow"}}},
+		{name: "only future key", configs: []config.SigningKeyConfig{{Kid: "a", Path: edPath, ActiveFrom: future}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := InitKeyring(tt.configs); err == nil {
+				t.Errorf("InitKeyring() error = nil, want error")
+			}
+		})
+	}
+}
+
+/* Key for verification is selected by kid of token and must match algorithm of token */
+func TestVerificationKey(t *testing.T) {
+	edKey := generateTestEd25519Key(t)
+	rsaKey := generateTestRSAKey(t, 2048)
+	expiredKey := generateTestEd25519Key(t)
+
+	initTestKeyring(t, []config.SigningKeyConfig{
+		{Kid: "rsa", Path: writeTestKey(t, "rsa", rsaKey), ActiveFrom: time.Now().Add(-48 * time.Hour).Format(time.RFC3339)},
+		{Kid: "ed", Path: writeTestKey(t, "ed", edKey), ActiveFrom: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+		{Kid: "expired", Path: writeTestKey(t, "expired", expiredKey), ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+	})
+
+	claims := jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+	sign := func(method jwt.SigningMethod, kid interface{}, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != nil {
+			token.Header["kid"] = kid
+		}
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return signed
+	}
+
+	signed, err := SignToken(claims)
+	if err != nil {
+		t.Fatalf("SignToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantKid string
+		wantErr bool
+	}{
+		{name: "signed by keyring", token: signed, wantKid: "ed"},
+		{name: "previous key", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey), wantKid: "rsa"},
+		{name: "no kid", token: sign(SigningMethodEdDSA, nil, edKey), wantErr: true},
+		{name: "kid of invalid type", token: sign(SigningMethodEdDSA, 1, edKey), wantErr: true},
+		{name: "unknown kid", token: sign(SigningMethodEdDSA, "unknown", edKey), wantErr: true},
+		{name: "expired key", token: sign(SigningMethodEdDSA, "expired", expiredKey), wantErr: true},
+		{name: "algorithm does not match key", token: sign(jwt.SigningMethodRS256, "ed", rsaKey), wantErr: true},
+		{name: "HMAC with kid of key", token: sign(jwt.SigningMethodHS256, "ed", []byte("secret")), wantErr: true},
+		{name: "signed by another key", token: sign(SigningMethodEdDSA, "ed", generateTestEd25519Key(t)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwt.Parse(tt.token, VerificationKey)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("jwt.Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && token.Header["kid"] != tt.wantKid {
+				t.Errorf("kid of token = %v, want %s", token.Header["kid"], tt.wantKid)
+			}
+		})
+	}
+}
+
+/* JWKS contains public keys of all keys, which are not expired, in format of RFC 7517 */
+func TestJWKS(t *testing.T) {
+	edKey := generateTestEd25519Key(t)
+	rsaKey := generateTestRSAKey(t, 2048)
+
+	initTestKeyring(t, []config.SigningKeyConfig{
+		{Kid: "ed", Path: writeTestKey(t, "ed", edKey)},
+		{Kid: "rsa", Path: writeTestKey(t, "rsa", rsaKey), ActiveFrom: time.Now().Add(24 * time.Hour).Format(time.RFC3339)},
+		{Kid: "expired", Path: writeTestKey(t, "expired", generateTestEd25519Key(t)), ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+	})
+
+	decode := func(value string) []byte {
+		data, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			t.Fatalf("invalid base64url value %q: %s", value, err)
+		}
+
+		return data
+	}
+
+	keys := make(map[string]bool)
+
+	for _, jwk := range JWKS().Keys {
+		keys[jwk.Kid] = true
+
+		if jwk.Use != "sig" {
+			t.Errorf("use of key %s = %q, want sig", jwk.Kid, jwk.Use)
+		}
+
+		switch jwk.Kid {
+		case "ed":
+			publicKey := edKey.Public().(ed25519.PublicKey)
+			if jwk.Kty != "OKP" || jwk.Crv != "Ed25519" || jwk.Alg != "EdDSA" || string(decode(jwk.X)) != string(publicKey) {
+				t.Errorf("JWK of Ed25519 key = %+v", jwk)
+			}
+
+		case "rsa":
+			n := new(big.Int).SetBytes(decode(jwk.N))
+			e := new(big.Int).SetBytes(decode(jwk.E))
+			if jwk.Kty != "RSA" || jwk.Alg != "RS256" || n.Cmp(rsaKey.N) != 0 || e.Int64() != int64(rsaKey.E) {
+				t.Errorf("JWK of RSA key = %+v", jwk)
+			}
+		}
+	}
+
+	for kid, want := range map[string]bool{"ed": true, "rsa": true, "expired": false} {
+		if keys[kid] != want {
+			t.Errorf("key %s is published = %v, want %v", kid, keys[kid], want)
+		}
+	}
+}
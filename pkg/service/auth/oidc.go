@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	config "main-server/config"
+	route "main-server/pkg/constant/route"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+/* Model of OpenID Connect discovery document */
+type oidcDiscoveryModel struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	RevocationEndpoint    string `json:"revocation_endpoint"`
+}
+
+/*
+* Provider of authorization through any OpenID Connect provider (Keycloak, Yandex, etc.)
+* (endpoints are received from discovery document of issuer on first use)
+ */
+type OIDCProvider struct {
+	name      string
+	issuer    string
+	cfg       config.OAuth2ProviderConfig
+	mutex     sync.Mutex
+	config    *oauth2.Config
+	discovery oidcDiscoveryModel
+}
+
+func NewOIDCProvider(name string, cfg config.OAuth2ProviderConfig) (*OIDCProvider, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("issuer is required for OpenID Connect provider " + name)
+	}
+
+	return &OIDCProvider{
+		name:   name,
+		issuer: strings.TrimRight(cfg.Issuer, "/"),
+		cfg:    cfg,
+	}, nil
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+/* Getting configuration of provider (discovery is repeated until it succeeds) */
+func (p *OIDCProvider) getConfig(ctx context.Context) (*oauth2.Config, oidcDiscoveryModel, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.config != nil {
+		return p.config, p.discovery, nil
+	}
+
+	var discovery oidcDiscoveryModel
+	if err := getJSON(ctx, p.issuer+route.OIDC_DISCOVERY_ROUTE, &discovery); err != nil {
+		return nil, oidcDiscoveryModel{}, err
+	}
+
+	if discovery.TokenEndpoint == "" || discovery.UserinfoEndpoint == "" {
+		return nil, oidcDiscoveryModel{}, errors.New("discovery document of " + p.issuer + " has no token or userinfo endpoint")
+	}
+
+	scopes := p.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	p.discovery = discovery
+	p.config = &oauth2.Config{
+		ClientID:     p.cfg.ClientId,
+		ClientSecret: p.cfg.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  firstNotEmpty(p.cfg.AuthUrl, discovery.AuthorizationEndpoint),
+			TokenURL: firstNotEmpty(p.cfg.TokenUrl, discovery.TokenEndpoint),
+		},
+		RedirectURL: firstNotEmpty(p.cfg.RedirectUrl, "http://localhost:3000"),
+		Scopes:      scopes,
+	}
+
+	return p.config, p.discovery, nil
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	cfg, _, err := p.getConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.AuthCodeURL(state), nil
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	cfg, _, err := p.getConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.Exchange(providerContext(ctx), code)
+}
+
+func (p *OIDCProvider) UserInfo(ctx context.Context, token *oauth2.Token) (userModel.ProviderUserModel, error) {
+	claims, err := p.getUserInfo(ctx, token.AccessToken)
+	if err != nil {
+		return userModel.ProviderUserModel{}, err
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return userModel.ProviderUserModel{}, errors.New("Провайдер не предоставил идентификатор пользователя!")
+	}
+
+	email, _ := claims["email"].(string)
+	givenName, _ := claims["given_name"].(string)
+	familyName, _ := claims["family_name"].(string)
+	name, _ := claims["name"].(string)
+	username, _ := claims["preferred_username"].(string)
+
+	// Некоторые провайдеры передают email_verified строкой
+	verified := false
+	switch value := claims["email_verified"].(type) {
+	case bool:
+		verified = value
+	case string:
+		verified = value == "true"
+	}
+
+	return userModel.ProviderUserModel{
+		Subject:       subject,
+		Email:         email,
+		EmailVerified: verified,
+		GivenName:     givenName,
+		FamilyName:    familyName,
+		Name:          firstNotEmpty(name, username, strings.TrimSpace(givenName+" "+familyName)),
+	}, nil
+}
+
+/* Access token is valid while userinfo endpoint accepts it */
+func (p *OIDCProvider) Verify(ctx context.Context, accessToken string) (bool, error) {
+	if _, err := p.getUserInfo(ctx, accessToken); err != nil {
+		var statusErr *ProviderStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (p *OIDCProvider) Refresh(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	cfg, _, err := p.getConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return refreshProviderToken(ctx, cfg, refreshToken)
+}
+
+/* Revoking token (RFC 7009), if provider supports it */
+func (p *OIDCProvider) Revoke(ctx context.Context, token string) error {
+	cfg, discovery, err := p.getConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if discovery.RevocationEndpoint == "" {
+		return nil
+	}
+
+	form := url.Values{}
+	form.Set("token", token)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.RevocationEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+
+	response, err := providerClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return &ProviderStatusError{StatusCode: response.StatusCode}
+	}
+
+	return nil
+}
+
+/* Getting claims of user from userinfo endpoint */
+func (p *OIDCProvider) getUserInfo(ctx context.Context, accessToken string) (map[string]interface{}, error) {
+	_, discovery, err := p.getConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.UserinfoEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+
+	claims := make(map[string]interface{})
+	if err := doJSON(request, &claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+/*
+* External OAuth2 / OpenID Connect authentication provider
+* (name of provider is the value of authentication type of user)
+ */
+type Provider interface {
+	// Name of provider (value of authentication type)
+	Name() string
+
+	// Address of authorization page of provider with state of authorization
+	AuthCodeURL(ctx context.Context, state string) (string, error)
+
+	// Exchange of authorization code to tokens of provider
+	Exchange(ctx context.Context, code string) (*oauth2.Token, error)
+
+	// Getting profile of user
+	UserInfo(ctx context.Context, token *oauth2.Token) (userModel.ProviderUserModel, error)
+
+	// Checking that access token of provider is still valid
+	Verify(ctx context.Context, accessToken string) (bool, error)
+
+	// Getting new access token by refresh token of provider (ErrRefreshNotSupported if provider does not support it)
+	Refresh(ctx context.Context, refreshToken string) (*oauth2.Token, error)
+
+	// Revoking token of provider
+	Revoke(ctx context.Context, token string) error
+}
+
+var ErrRefreshNotSupported = errors.New("Провайдер не поддерживает обновление токена доступа!")
+
+var (
+	providersMutex sync.RWMutex
+	providers      = make(map[string]Provider)
+)
+
+/* Registration of provider (provider with the same name is replaced) */
+func RegisterProvider(provider Provider) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+
+	providers[provider.Name()] = provider
+}
+
+/* Getting provider by name */
+func GetProvider(name string) (Provider, bool) {
+	providersMutex.RLock()
+	defer providersMutex.RUnlock()
+
+	provider, ok := providers[name]
+
+	return provider, ok
+}
+
+/* Getting names of all registered providers */
+func ProviderNames() []string {
+	providersMutex.RLock()
+	defer providersMutex.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+/* Creating provider by its configuration */
+func NewProvider(name string, cfg config.OAuth2ProviderConfig) (Provider, error) {
+	switch strings.ToLower(cfg.Type) {
+	case authConstants.PROVIDER_TYPE_GOOGLE:
+		return NewGoogleProvider(name, cfg), nil
+
+	case authConstants.PROVIDER_TYPE_VK:
+		return NewVKProvider(name, cfg), nil
+
+	case authConstants.PROVIDER_TYPE_OIDC:
+		return NewOIDCProvider(name, cfg)
+	}
+
+	return nil, fmt.Errorf("unknown type of provider %s: %s", name, cfg.Type)
+}
+
+/* Registration of all providers from configuration */
+func InitProviders(configs map[string]config.OAuth2ProviderConfig) error {
+	for name, cfg := range configs {
+		provider, err := NewProvider(strings.ToLower(name), cfg)
+		if err != nil {
+			return err
+		}
+
+		RegisterProvider(provider)
+	}
+
+	return nil
+}
+
+/* Generation of random state of authorization through provider */
+func GenerateState() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(data), nil
+}
+
+/* HTTP client for requests to providers */
+var providerClient = &http.Client{
+	Timeout: authConstants.PROVIDER_HTTP_TIMEOUT,
+}
+
+/* Context of requests to providers (oauth2 package uses HTTP client from context) */
+func providerContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, providerClient)
+}
+
+/* Refreshing access token through token endpoint of provider */
+func refreshProviderToken(ctx context.Context, cfg *oauth2.Config, refreshToken string) (*oauth2.Token, error) {
+	token, err := cfg.TokenSource(providerContext(ctx), &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+
+	// Провайдер может не возвращать новый токен обновления
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+
+	return token, nil
+}
+
+/* GET request to provider with decoding of JSON response */
+func getJSON(ctx context.Context, address string, data interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return err
+	}
+
+	return doJSON(request, data)
+}
+
+/* Execution of request to provider with decoding of JSON response */
+func doJSON(request *http.Request, data interface{}) error {
+	response, err := providerClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return &ProviderStatusError{StatusCode: response.StatusCode}
+	}
+
+	return json.NewDecoder(response.Body).Decode(data)
+}
+
+/* Error of provider response with unexpected status */
+type ProviderStatusError struct {
+	StatusCode int
+}
+
+func (e *ProviderStatusError) Error() string {
+	return "unexpected status of provider response: " + http.StatusText(e.StatusCode)
+}
+
+/* Getting the first non-empty value */
+func firstNotEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
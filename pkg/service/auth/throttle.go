@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+/* Error of throttled attempt of authentication (attempt can be repeated after delay) */
+type ThrottleError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottleError) Error() string {
+	return fmt.Sprintf("Слишком много попыток! Повторите попытку через %d сек.", e.RetryAfterSeconds())
+}
+
+/* Delay before next attempt in whole seconds (for Retry-After header) */
+func (e *ThrottleError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
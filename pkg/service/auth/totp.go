@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"image/png"
+	authConstants "main-server/pkg/constant/auth"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+/* Generating a new TOTP secret for the account (secret, otpauth URI and QR code in PNG) */
+func GenerateTotpKey(account string) (string, string, []byte, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      authConstants.MFA_ISSUER,
+		AccountName: account,
+		Period:      authConstants.MFA_PERIOD,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	image, err := key.Image(authConstants.MFA_QR_SIZE, authConstants.MFA_QR_SIZE)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, image); err != nil {
+		return "", "", nil, err
+	}
+
+	return key.Secret(), key.URL(), buffer.Bytes(), nil
+}
+
+/*
+* Validating the TOTP code with the allowed clock skew
+* (returns the time step of the code, codes of already used steps are rejected)
+ */
+func ValidateTotpCode(secret, code string, lastStep int64) (int64, bool) {
+	return validateTotpCodeAt(secret, code, lastStep, time.Now())
+}
+
+func validateTotpCodeAt(secret, code string, lastStep int64, date time.Time) (int64, bool) {
+	now := date.Unix() / authConstants.MFA_PERIOD
+
+	for offset := int64(-authConstants.MFA_SKEW); offset <= authConstants.MFA_SKEW; offset++ {
+		step := now + offset
+		if step <= lastStep {
+			continue
+		}
+
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*authConstants.MFA_PERIOD, 0), totp.ValidateOpts{
+			Period:    authConstants.MFA_PERIOD,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+/* Generating one-time recovery codes (format xxxxx-xxxxx) */
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+
+	for i := 0; i < count; i++ {
+		data := make([]byte, 5)
+		if _, err := rand.Read(data); err != nil {
+			return nil, err
+		}
+
+		code := hex.EncodeToString(data)
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+
+	return codes, nil
+}
+
+/* Recovery codes are compared without separators and case */
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+/* QR code is passed to the client as data URI */
+func PngDataUri(data []byte) string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(data)
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	authConstants "main-server/pkg/constant/auth"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const testTotpSecret = "JBSWY3DPEHPK3PXP"
+
+func totpCodeAtStep(t *testing.T, step int64) string {
+	code, err := totp.GenerateCodeCustom(testTotpSecret, time.Unix(step*authConstants.MFA_PERIOD, 0), totp.ValidateOpts{
+		Period:    authConstants.MFA_PERIOD,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code
+}
+
+/* Codes of neighbouring time steps are accepted within skew, used steps are rejected */
+func TestValidateTotpCode(t *testing.T) {
+	const step int64 = 55000000
+	now := time.Unix(step*authConstants.MFA_PERIOD+authConstants.MFA_PERIOD/2, 0)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOk   bool
+	}{
+		{name: "current step", code: totpCodeAtStep(t, step), wantStep: step, wantOk: true},
+		{name: "previous step within skew", code: totpCodeAtStep(t, step-authConstants.MFA_SKEW), wantStep: step - authConstants.MFA_SKEW, wantOk: true},
+		{name: "next step within skew", code: totpCodeAtStep(t, step+authConstants.MFA_SKEW), wantStep: step + authConstants.MFA_SKEW, wantOk: true},
+		{name: "step before skew", code: totpCodeAtStep(t, step-authConstants.MFA_SKEW-1)},
+		{name: "step after skew", code: totpCodeAtStep(t, step+authConstants.MFA_SKEW+1)},
+		{name: "used step", code: totpCodeAtStep(t, step), lastStep: step},
+		{name: "later step is used", code: totpCodeAtStep(t, step-1), lastStep: step},
+		{name: "unused step after used one", code: totpCodeAtStep(t, step+1), lastStep: step, wantStep: step + 1, wantOk: true},
+		{name: "wrong code", code: "000000"},
+		{name: "empty code", code: ""},
+		{name: "code with spaces", code: " " + totpCodeAtStep(t, step)},
+		{name: "invalid secret", secret: "not base32!", code: totpCodeAtStep(t, step)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := tt.secret
+			if secret == "" {
+				secret = testTotpSecret
+			}
+
+			gotStep, gotOk := validateTotpCodeAt(secret, tt.code, tt.lastStep, now)
+			if gotOk != tt.wantOk || gotStep != tt.wantStep {
+				t.Errorf("validateTotpCodeAt() = (%d, %v), want (%d, %v)", gotStep, gotOk, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+/* Recovery codes are compared without separators, spaces and case */
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{code: "abcde-12345", want: "abcde12345"},
+		{code: "ABCDE-12345", want: "abcde12345"},
+		{code: "  abcde12345 ", want: "abcde12345"},
+		{code: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeRecoveryCode(tt.code); got != tt.want {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
+	route "main-server/pkg/constant/route"
+	userModel "main-server/pkg/model/user"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/vk"
+)
+
+/* Provider of authorization through VK */
+type VKProvider struct {
+	name   string
+	config oauth2.Config
+	apiUrl string
+}
+
+func NewVKProvider(name string, cfg config.OAuth2ProviderConfig) *VKProvider {
+	// Адреса VK могут быть переопределены в конфигурации (например, для локального тестового сервера)
+	endpoint := vk.Endpoint
+	endpoint.AuthURL = firstNotEmpty(cfg.AuthUrl, endpoint.AuthURL)
+	endpoint.TokenURL = firstNotEmpty(cfg.TokenUrl, endpoint.TokenURL)
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"email"}
+	}
+
+	return &VKProvider{
+		name: name,
+		config: oauth2.Config{
+			ClientID:     cfg.ClientId,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     endpoint,
+			RedirectURL:  firstNotEmpty(cfg.RedirectUrl, "http://localhost:3000"),
+			Scopes:       scopes,
+		},
+		apiUrl: strings.TrimRight(firstNotEmpty(cfg.ApiUrl, route.VK_API_ROUTE), "/"),
+	}
+}
+
+func (p *VKProvider) Name() string {
+	return p.name
+}
+
+func (p *VKProvider) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	return p.config.AuthCodeURL(state), nil
+}
+
+func (p *VKProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return p.config.Exchange(providerContext(ctx), code)
+}
+
+/*
+* Getting profile of VK user
+* (VK returns email and identifier of user together with access token instead of separate endpoint)
+ */
+func (p *VKProvider) UserInfo(ctx context.Context, token *oauth2.Token) (userModel.ProviderUserModel, error) {
+	email, _ := token.Extra("email").(string)
+	if email == "" {
+		return userModel.ProviderUserModel{}, errors.New("VK не предоставил email-адрес пользователя!")
+	}
+
+	var userId int64
+	switch value := token.Extra("user_id").(type) {
+	case float64:
+		userId = int64(value)
+	case string:
+		userId, _ = strconv.ParseInt(value, 10, 64)
+	}
+
+	if userId == 0 {
+		return userModel.ProviderUserModel{}, errors.New("VK не предоставил идентификатор пользователя!")
+	}
+
+	profile, err := p.getUser(ctx, token.AccessToken, userId)
+	if err != nil {
+		return userModel.ProviderUserModel{}, err
+	}
+
+	return userModel.ProviderUserModel{
+		Subject: strconv.FormatInt(userId, 10),
+		Email:   email,
+		// VK передаёт только подтверждённый email-адрес
+		EmailVerified: true,
+		GivenName:     profile.FirstName,
+		FamilyName:    profile.LastName,
+		Name:          strings.TrimSpace(profile.FirstName + " " + profile.LastName),
+	}, nil
+}
+
+/* Token of VK is valid while API accepts it */
+func (p *VKProvider) Verify(ctx context.Context, accessToken string) (bool, error) {
+	if _, err := p.getUser(ctx, accessToken, 0); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* VK does not issue refresh tokens */
+func (p *VKProvider) Refresh(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	return nil, ErrRefreshNotSupported
+}
+
+/* VK does not provide revocation of user tokens */
+func (p *VKProvider) Revoke(ctx context.Context, token string) error {
+	return nil
+}
+
+/* Getting profile of VK user (method users.get, current user if userId is 0) */
+func (p *VKProvider) getUser(ctx context.Context, accessToken string, userId int64) (userModel.VKUserModel, error) {
+	params := url.Values{}
+	if userId != 0 {
+		params.Set("user_ids", strconv.FormatInt(userId, 10))
+	}
+	params.Set("access_token", accessToken)
+	params.Set("v", authConstants.VK_API_VERSION)
+
+	var j userModel.VKUsersResponseModel
+	if err := getJSON(ctx, p.apiUrl+route.VK_USERS_GET_METHOD+"?"+params.Encode(), &j); err != nil {
+		return userModel.VKUserModel{}, err
+	}
+
+	if j.Error != nil {
+		return userModel.VKUserModel{}, fmt.Errorf("VK API: %s", j.Error.ErrorMsg)
+	}
+
+	if len(j.Response) == 0 {
+		return userModel.VKUserModel{}, errors.New("Пользователь VK не найден!")
+	}
+
+	return j.Response[0], nil
+}
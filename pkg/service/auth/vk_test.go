@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	config "main-server/config"
+	route "main-server/pkg/constant/route"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+/* Local fake VK server: token endpoint and method users.get of API */
+func newFakeVKServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %s", err)
+		}
+
+		if r.Form.Get("code") != "valid-code" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "vk-access-token",
+			"expires_in":   86400,
+			"user_id":      42,
+			"email":        "user@example.com",
+		})
+	})
+
+	mux.HandleFunc("/method"+route.VK_USERS_GET_METHOD, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("access_token") != "vk-access-token" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{"error_code": 5, "error_msg": "User authorization failed"},
+			})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": []map[string]interface{}{
+				{"id": 42, "first_name": "Иван", "last_name": "Петров"},
+			},
+		})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func newFakeVKProvider(serverUrl string) *VKProvider {
+	return NewVKProvider("vk", config.OAuth2ProviderConfig{
+		ClientId:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectUrl:  "http://localhost/auth/sign-in/vk/callback",
+		AuthUrl:      serverUrl + "/authorize",
+		TokenUrl:     serverUrl + "/access_token",
+		ApiUrl:       serverUrl + "/method",
+	})
+}
+
+func TestVKProviderAuthCodeURL(t *testing.T) {
+	provider := newFakeVKProvider("http://vk.local")
+
+	address, err := provider.AuthCodeURL(context.Background(), "state-value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := url.Parse(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Host != "vk.local" || parsed.Path != "/authorize" {
+		t.Errorf("unexpected authorization address %s", address)
+	}
+
+	if parsed.Query().Get("state") != "state-value" {
+		t.Errorf("state is not passed to provider: %s", address)
+	}
+}
+
+func TestVKProviderExchangeAndUserInfo(t *testing.T) {
+	server := newFakeVKServer(t)
+	defer server.Close()
+
+	provider := newFakeVKProvider(server.URL)
+	ctx := context.Background()
+
+	token, err := provider.Exchange(ctx, "valid-code")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != "vk-access-token" {
+		t.Errorf("unexpected access token %q", token.AccessToken)
+	}
+
+	user, err := provider.UserInfo(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Subject != "42" || user.Email != "user@example.com" || !user.EmailVerified {
+		t.Errorf("unexpected user %+v", user)
+	}
+
+	if user.Name != "Иван Петров" {
+		t.Errorf("unexpected name %q", user.Name)
+	}
+
+	ok, err := provider.Verify(ctx, token.AccessToken)
+	if err != nil || !ok {
+		t.Errorf("valid token is not verified: %v", err)
+	}
+
+	if ok, _ := provider.Verify(ctx, "revoked-token"); ok {
+		t.Error("invalid token is verified")
+	}
+}
+
+func TestVKProviderExchangeInvalidCode(t *testing.T) {
+	server := newFakeVKServer(t)
+	defer server.Close()
+
+	if _, err := newFakeVKProvider(server.URL).Exchange(context.Background(), "invalid-code"); err == nil {
+		t.Error("invalid code is exchanged")
+	}
+}
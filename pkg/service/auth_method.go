@@ -0,0 +1,43 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Structure for this service */
+type AuthMethodService struct {
+	repo repository.AuthMethod
+}
+
+/* Function for create new service */
+func NewAuthMethodService(repo repository.AuthMethod) *AuthMethodService {
+	return &AuthMethodService{
+		repo: repo,
+	}
+}
+
+/* Get all login methods of user */
+func (s *AuthMethodService) GetAuthMethods(usersId int) (userModel.AuthMethodsModel, error) {
+	return s.repo.GetAuthMethods(usersId)
+}
+
+/* Link account of external provider to user */
+func (s *AuthMethodService) LinkAuthMethod(usersId int, provider, code string) (userModel.AuthMethodsModel, error) {
+	return s.repo.LinkAuthMethod(usersId, provider, code)
+}
+
+/* Unlink login method from user */
+func (s *AuthMethodService) UnlinkAuthMethod(usersId int, value string) (userModel.AuthMethodsModel, error) {
+	return s.repo.UnlinkAuthMethod(usersId, value)
+}
+
+/* Set password to user without local login method */
+func (s *AuthMethodService) SetPassword(usersId int, data userModel.SetPasswordModel) (userModel.AuthMethodsModel, error) {
+	return s.repo.SetPassword(usersId, data)
+}
+
+/* Check that access granted by external provider is still valid */
+func (s *AuthMethodService) VerifyAuthMethod(usersId int, value string) (bool, error) {
+	return s.repo.VerifyAuthMethod(usersId, value)
+}
@@ -17,7 +17,27 @@ func NewGuestService(repo repository.Guest) *GuestService {
 	}
 }
 
-/* Get all articles */
-func (s *GuestService) GetArticles() (articleModel.ArticlesModel, error) {
-	return s.repo.GetArticles()
+/* Get all published articles */
+func (s *GuestService) GetArticles(domainsId int, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error) {
+	return s.repo.GetArticles(domainsId, filter)
+}
+
+/* Get published article */
+func (s *GuestService) GetArticle(domainsId int, uuid articleModel.ArticleUuidModel) (articleModel.ArticleModel, error) {
+	return s.repo.GetArticle(domainsId, uuid)
+}
+
+/* Full-text search of published articles */
+func (s *GuestService) SearchArticles(domainsId int, search articleModel.ArticlesSearchRequestModel) (articleModel.ArticlesSearchModel, error) {
+	return s.repo.SearchArticles(domainsId, search)
+}
+
+/* Get tag cloud of published articles */
+func (s *GuestService) GetTags(domainsId int) (articleModel.TagsModel, error) {
+	return s.repo.GetTags(domainsId)
+}
+
+/* Get published articles by tag */
+func (s *GuestService) GetArticlesByTag(domainsId int, data articleModel.ArticlesByTagRequestModel) (articleModel.ArticlesModel, error) {
+	return s.repo.GetArticlesByTag(domainsId, data)
 }
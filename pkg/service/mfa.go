@@ -0,0 +1,38 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Structure for this service */
+type MfaService struct {
+	repo repository.Mfa
+}
+
+/* Function for create new service */
+func NewMfaService(repo repository.Mfa) *MfaService {
+	return &MfaService{
+		repo: repo,
+	}
+}
+
+/* Start enrollment of two-factor authentication */
+func (s *MfaService) EnrollMfa(usersId int) (userModel.MfaEnrollModel, error) {
+	return s.repo.EnrollMfa(usersId)
+}
+
+/* Confirm enrollment with the first code */
+func (s *MfaService) ConfirmMfa(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaRecoveryCodesModel, error) {
+	return s.repo.ConfirmMfa(usersId, data, session)
+}
+
+/* Disable two-factor authentication */
+func (s *MfaService) DisableMfa(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaStatusModel, error) {
+	return s.repo.DisableMfa(usersId, data, session)
+}
+
+/* Regenerate recovery codes */
+func (s *MfaService) RegenerateRecoveryCodes(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaRecoveryCodesModel, error) {
+	return s.repo.RegenerateRecoveryCodes(usersId, data, session)
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"errors"
 	articleModel "main-server/pkg/model/article"
 	repository "main-server/pkg/repository"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,6 +27,30 @@ func (s *ModeratorService) GetUncheckedArticle(uuid articleModel.ArticleUuidMode
 }
 
 /* Method for get all unchecked articles */
-func (s *ModeratorService) GetUncheckedArticles(c *gin.Context) (articleModel.ArticlesModel, error) {
-	return s.repo.GetUncheckedArticles(c)
+func (s *ModeratorService) GetUncheckedArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error) {
+	return s.repo.GetUncheckedArticles(c, filter)
+}
+
+/* Method for approve unchecked article */
+func (s *ModeratorService) ApproveArticle(c *gin.Context, data articleModel.ArticleCheckRequestModel) (articleModel.ArticleSuccessModel, error) {
+	return s.repo.CheckArticle(c, data, true)
+}
+
+/* Method for reject unchecked article (reason is required) */
+func (s *ModeratorService) RejectArticle(c *gin.Context, data articleModel.ArticleCheckRequestModel) (articleModel.ArticleSuccessModel, error) {
+	if strings.TrimSpace(data.Reason) == "" {
+		return articleModel.ArticleSuccessModel{}, errors.New("Необходимо указать причину отклонения статьи!")
+	}
+
+	return s.repo.CheckArticle(c, data, false)
+}
+
+/* Method for get history of moderator decisions for article */
+func (s *ModeratorService) GetArticleChecks(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleCheckedHistoryModel, error) {
+	return s.repo.GetArticleChecks(uuid, c)
+}
+
+/* Method for get changes of article since its last approval */
+func (s *ModeratorService) GetArticleChanges(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleRevisionDiffModel, error) {
+	return s.repo.GetArticleChanges(uuid, c)
 }
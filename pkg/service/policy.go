@@ -0,0 +1,22 @@
+package service
+
+import (
+	repository "main-server/pkg/repository"
+)
+
+/* Structure for this service */
+type PolicyService struct {
+	repo repository.Policy
+}
+
+/* Function for create new service */
+func NewPolicyService(repo repository.Policy) *PolicyService {
+	return &PolicyService{
+		repo: repo,
+	}
+}
+
+/* Check permission of user for action with object */
+func (s *PolicyService) Enforce(usersId, domainsId int, object, action string) (bool, error) {
+	return s.repo.Enforce(usersId, domainsId, object, action)
+}
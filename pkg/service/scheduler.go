@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	repository "main-server/pkg/repository"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/* Structure for this service */
+type SchedulerService struct {
+	repo repository.Scheduler
+}
+
+/* Function for create new service */
+func NewSchedulerService(repo repository.Scheduler) *SchedulerService {
+	return &SchedulerService{
+		repo: repo,
+	}
+}
+
+/* Publish approved articles whose publishing time has come */
+func (s *SchedulerService) PublishScheduledArticles() (int64, error) {
+	return s.repo.PublishScheduledArticles()
+}
+
+/* Permanently delete articles that stay in trash longer than the retention period */
+func (s *SchedulerService) PurgeDeletedArticles(retention time.Duration) (int64, error) {
+	return s.repo.PurgeDeletedArticles(retention)
+}
+
+/*
+* Run publishing of scheduled articles and purging of trash with the interval
+* until the context is cancelled
+ */
+func (s *SchedulerService) Run(ctx context.Context, interval, trashRetention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.PublishScheduledArticles()
+			if err != nil {
+				logrus.Errorf("error occured while publishing scheduled articles: %s", err.Error())
+			} else if count > 0 {
+				logrus.Infof("published scheduled articles: %d", count)
+			}
+
+			count, err = s.PurgeDeletedArticles(trashRetention)
+			if err != nil {
+				logrus.Errorf("error occured while purging deleted articles: %s", err.Error())
+			} else if count > 0 {
+				logrus.Infof("purged deleted articles: %d", count)
+			}
+		}
+	}
+}
@@ -1,31 +1,40 @@
 package service
 
 import (
+	"context"
 	articleModel "main-server/pkg/model/article"
 	rbacModel "main-server/pkg/model/rbac"
 	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Authorization interface {
-	CreateUser(user userModel.UserRegisterModel) (userModel.UserAuthDataModel, error)
-	LoginUser(user userModel.UserLoginModel) (userModel.UserAuthDataModel, error)
-	LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error)
-	Refresh(data userModel.TokenLogoutDataModel, refreshToken string) (userModel.UserAuthDataModel, error)
+	CreateUser(user userModel.UserRegisterModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
+	LoginUser(user userModel.UserLoginModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
+	LoginUserProvider(provider, code string, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
+	LoginUserMfa(data userModel.UserMfaLoginModel, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
+	Refresh(data userModel.TokenLogoutDataModel, refreshToken string, domain rbacModel.DomainModel, session userModel.SessionDataModel) (userModel.UserAuthDataModel, error)
 	Logout(tokens userModel.TokenLogoutDataModel) (bool, error)
 	Activate(link string) (bool, error)
+	ResendActivation(email string, session userModel.SessionDataModel) (bool, error)
+	IsActivated(usersId int) (bool, error)
+	UnlockAccount(link string) (bool, error)
 
 	// Recover password
-	RecoveryPassword(email string) (bool, error)
+	RecoveryPassword(email string, session userModel.SessionDataModel) (bool, error)
 	ResetPassword(data userModel.ResetPasswordModel) (bool, error)
 }
 
 type Token interface {
-	ParseToken(token, signingKey string) (userModel.TokenOutputParse, error)
+	ParseToken(token string) (userModel.TokenOutputParse, error)
 	ParseTokenWithoutValid(token, signingKey string) (userModel.TokenOutputParse, error)
+	ParseAccessTokenWithoutValid(token string) (userModel.TokenOutputParse, error)
 	ParseResetToken(pToken, signingKey string) (userModel.ResetTokenOutputParse, error)
+	ParseMfaToken(pToken, signingKey string) (userModel.MfaTokenOutputParse, error)
+	GetJWKS() userModel.JWKSModel
 }
 
 type AuthType interface {
@@ -38,7 +47,21 @@ type User interface {
 	UpdateArticle(c *gin.Context, data articleModel.ArticleUpdateRequestModel) (bool, error)
 	DeleteArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
 	GetArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleModel, error)
-	GetArticles(c *gin.Context) (articleModel.ArticlesModel, error)
+	GetArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error)
+	GetTrashArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error)
+	RestoreArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+	UpdateArticleStatus(data articleModel.ArticleStatusRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+	SearchArticles(c *gin.Context, search articleModel.ArticlesSearchRequestModel) (articleModel.ArticlesSearchModel, error)
+
+	// Article revisions
+	GetArticleRevisions(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleRevisionsModel, error)
+	GetArticleRevisionsDiff(data articleModel.ArticleRevisionDiffRequestModel, c *gin.Context) (articleModel.ArticleRevisionDiffModel, error)
+	RestoreArticleRevision(data articleModel.ArticleRevisionRestoreRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+
+	// Article sharing
+	GrantArticleAccess(data articleModel.ArticleGrantRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+	RevokeArticleAccess(data articleModel.ArticleRevokeRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error)
+	GetArticleGrants(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleGrantsModel, error)
 
 	// Profile
 	GetProfile(c *gin.Context) (userModel.UserProfileModel, error)
@@ -47,11 +70,76 @@ type User interface {
 
 type Moderator interface {
 	GetUncheckedArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleModel, error)
-	GetUncheckedArticles(c *gin.Context) (articleModel.ArticlesModel, error)
+	GetUncheckedArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error)
+	ApproveArticle(c *gin.Context, data articleModel.ArticleCheckRequestModel) (articleModel.ArticleSuccessModel, error)
+	RejectArticle(c *gin.Context, data articleModel.ArticleCheckRequestModel) (articleModel.ArticleSuccessModel, error)
+	GetArticleChecks(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleCheckedHistoryModel, error)
+	GetArticleChanges(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleRevisionDiffModel, error)
+}
+
+type Admin interface {
+	// Domains
+	CreateDomain(c *gin.Context, data rbacModel.DomainCreateRequestModel) (rbacModel.DomainModel, error)
+	UpdateDomain(c *gin.Context, data rbacModel.DomainUpdateRequestModel) (rbacModel.DomainModel, error)
+	DeleteDomain(c *gin.Context, data rbacModel.DomainUuidModel) (rbacModel.RbacSuccessModel, error)
+	GetDomain(c *gin.Context, data rbacModel.DomainUuidModel) (rbacModel.DomainModel, error)
+	GetDomains(c *gin.Context) (rbacModel.DomainsModel, error)
+
+	// Roles
+	CreateRole(c *gin.Context, data rbacModel.RoleCreateRequestModel) (rbacModel.RoleModel, error)
+	UpdateRole(c *gin.Context, data rbacModel.RoleUpdateRequestModel) (rbacModel.RoleModel, error)
+	DeleteRole(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RbacSuccessModel, error)
+	GetRole(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RoleModel, error)
+	GetRoles(c *gin.Context, data rbacModel.RolesRequestModel) (rbacModel.RolesModel, error)
+
+	// Role assignments
+	AssignRole(c *gin.Context, data rbacModel.RoleAssignRequestModel) (rbacModel.RbacSuccessModel, error)
+	RemoveRole(c *gin.Context, data rbacModel.RoleAssignRequestModel) (rbacModel.RbacSuccessModel, error)
+	GetRoleUsers(c *gin.Context, data rbacModel.RoleUuidModel) (rbacModel.RoleUsersModel, error)
+
+	// Users
+	ForceLogout(c *gin.Context, data rbacModel.UserUuidModel) (userModel.SessionRevokeModel, error)
 }
 
 type Guest interface {
-	GetArticles() (articleModel.ArticlesModel, error)
+	GetArticles(domainsId int, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error)
+	GetArticle(domainsId int, uuid articleModel.ArticleUuidModel) (articleModel.ArticleModel, error)
+	SearchArticles(domainsId int, search articleModel.ArticlesSearchRequestModel) (articleModel.ArticlesSearchModel, error)
+
+	// Tags
+	GetTags(domainsId int) (articleModel.TagsModel, error)
+	GetArticlesByTag(domainsId int, data articleModel.ArticlesByTagRequestModel) (articleModel.ArticlesModel, error)
+}
+
+type Scheduler interface {
+	PublishScheduledArticles() (int64, error)
+	PurgeDeletedArticles(retention time.Duration) (int64, error)
+	Run(ctx context.Context, interval, trashRetention time.Duration)
+}
+
+type Policy interface {
+	Enforce(usersId, domainsId int, object, action string) (bool, error)
+}
+
+type Session interface {
+	GetSessions(usersId int, current string) (userModel.SessionsModel, error)
+	RevokeSession(usersId int, uuid string) (userModel.SessionRevokeModel, error)
+	RevokeOtherSessions(usersId int, current string) (userModel.SessionRevokeModel, error)
+}
+
+type Mfa interface {
+	EnrollMfa(usersId int) (userModel.MfaEnrollModel, error)
+	ConfirmMfa(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaRecoveryCodesModel, error)
+	DisableMfa(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaStatusModel, error)
+	RegenerateRecoveryCodes(usersId int, data userModel.MfaCodeModel, session userModel.SessionDataModel) (userModel.MfaRecoveryCodesModel, error)
+}
+
+type AuthMethod interface {
+	GetAuthMethods(usersId int) (userModel.AuthMethodsModel, error)
+	LinkAuthMethod(usersId int, provider, code string) (userModel.AuthMethodsModel, error)
+	UnlinkAuthMethod(usersId int, value string) (userModel.AuthMethodsModel, error)
+	SetPassword(usersId int, data userModel.SetPasswordModel) (userModel.AuthMethodsModel, error)
+	VerifyAuthMethod(usersId int, value string) (bool, error)
 }
 
 type Domain interface {
@@ -71,10 +159,16 @@ type Service struct {
 	Domain
 	Role
 	Guest
+	Scheduler
+	Policy
+	Admin
+	Session
+	Mfa
+	AuthMethod
 }
 
 func NewService(repos *repository.Repository) *Service {
-	tokenService := NewTokenService(repos.Role, repos.User, repos.AuthType)
+	tokenService := NewTokenService(repos.Role, repos.User, repos.AuthType, repos.TokenRevocation)
 
 	return &Service{
 		Token:         tokenService,
@@ -84,5 +178,11 @@ func NewService(repos *repository.Repository) *Service {
 		Guest:         NewGuestService(repos.Guest),
 		Domain:        NewDomainService(repos.Domain),
 		Role:          NewRoleService(repos.Role),
+		Scheduler:     NewSchedulerService(repos.Scheduler),
+		Policy:        NewPolicyService(repos.Policy),
+		Admin:         NewAdminService(repos.Admin),
+		Session:       NewSessionService(repos.Session),
+		Mfa:           NewMfaService(repos.Mfa),
+		AuthMethod:    NewAuthMethodService(repos.AuthMethod),
 	}
 }
@@ -0,0 +1,33 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Structure for this service */
+type SessionService struct {
+	repo repository.Session
+}
+
+/* Function for create new service */
+func NewSessionService(repo repository.Session) *SessionService {
+	return &SessionService{
+		repo: repo,
+	}
+}
+
+/* Get all sessions of user */
+func (s *SessionService) GetSessions(usersId int, current string) (userModel.SessionsModel, error) {
+	return s.repo.GetSessions(usersId, current)
+}
+
+/* Revoke session of user */
+func (s *SessionService) RevokeSession(usersId int, uuid string) (userModel.SessionRevokeModel, error) {
+	return s.repo.RevokeSession(usersId, uuid)
+}
+
+/* Revoke all sessions of user except current */
+func (s *SessionService) RevokeOtherSessions(usersId int, current string) (userModel.SessionRevokeModel, error) {
+	return s.repo.RevokeOtherSessions(usersId, current)
+}
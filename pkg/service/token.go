@@ -4,67 +4,85 @@ import (
 	"errors"
 	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
+	authService "main-server/pkg/service/auth"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
 /* Structure of current repository */
 type TokenService struct {
-	role     repository.Role
-	user     repository.User
-	authType repository.AuthType
+	role       repository.Role
+	user       repository.User
+	authType   repository.AuthType
+	revocation repository.TokenRevocation
 }
 
 /* Function create a new service */
 func NewTokenService(role repository.Role,
 	user repository.User,
 	authType repository.AuthType,
+	revocation repository.TokenRevocation,
 ) *TokenService {
 	return &TokenService{
-		role:     role,
-		user:     user,
-		authType: authType,
+		role:       role,
+		user:       user,
+		authType:   authType,
+		revocation: revocation,
 	}
 }
 
 /* Structure body token for user */
 type tokenClaims struct {
 	jwt.StandardClaims
-	UsersId     string  `json:"users_id"`      // ID for user
-	AuthTypesId string  `json:"auth_types_id"` // Type auth for user
-	TokenApi    *string `json:"token_api"`     // External token access
+	UsersId      string  `json:"users_id"`      // ID for user
+	AuthTypesId  string  `json:"auth_types_id"` // Type auth for user
+	TokenApi     *string `json:"token_api"`     // External token access
+	DomainsId    string  `json:"domains_id"`    // Domain for which token was issued
+	SessionsId   string  `json:"sessions_id"`   // Session (device) of user
+	TokenVersion int     `json:"token_version"` // Version of access tokens of user
 }
 
-/* Parse token with validate check */
-func (s *TokenService) ParseToken(pToken, signingKey string) (userModel.TokenOutputParse, error) {
-	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
+/*
+* Parse access token with validate check (signature is verified by public key of keyring,
+* token must not be revoked and its version must be equal to current version of user)
+ */
+func (s *TokenService) ParseToken(pToken string) (userModel.TokenOutputParse, error) {
+	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, authService.VerificationKey)
 
-		return []byte(signingKey), nil
-	})
+	if err != nil {
+		return userModel.TokenOutputParse{}, err
+	}
 
 	if !token.Valid {
 		return userModel.TokenOutputParse{}, errors.New("token is not valid")
 	}
 
-	if err != nil {
-		return userModel.TokenOutputParse{}, err
-	}
-
 	/* Get data from token */
 	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
 		return userModel.TokenOutputParse{}, errors.New("token claims are not of type")
 	}
 
+	revoked, err := s.revocation.IsTokenRevoked(claims.Id)
+
+	if err != nil {
+		return userModel.TokenOutputParse{}, err
+	}
+
+	if revoked {
+		return userModel.TokenOutputParse{}, errors.New("token is revoked")
+	}
+
 	user, err := s.user.GetUser("uuid", claims.UsersId)
 
 	if err != nil {
 		return userModel.TokenOutputParse{}, err
 	}
 
+	if claims.TokenVersion != user.TokenVersion {
+		return userModel.TokenOutputParse{}, errors.New("token is revoked")
+	}
+
 	authType, err := s.authType.GetAuthType("uuid", claims.AuthTypesId)
 
 	if err != nil {
@@ -72,21 +90,41 @@ func (s *TokenService) ParseToken(pToken, signingKey string) (userModel.TokenOut
 	}
 
 	return userModel.TokenOutputParse{
-		UsersId:  user.Id,
-		AuthType: authType,
-		TokenApi: claims.TokenApi,
+		UsersId:     user.Id,
+		AuthType:    authType,
+		TokenApi:    claims.TokenApi,
+		DomainUuid:  claims.DomainsId,
+		SessionUuid: claims.SessionsId,
+		TokenId:     claims.Id,
 	}, nil
 }
 
-/* Parse token without validate check */
+/* Parse refresh token without validate check of expiration (refresh token is signed by secret key) */
 func (s *TokenService) ParseTokenWithoutValid(pToken, signingKey string) (userModel.TokenOutputParse, error) {
-	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+	return s.parseTokenWithoutValid(pToken, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
 		}
 
-		return []byte(signingKey), nil
+		return authService.HmacKey(signingKey)
 	})
+}
+
+/* Parse access token without validate check of expiration (signature is verified by public key of keyring) */
+func (s *TokenService) ParseAccessTokenWithoutValid(pToken string) (userModel.TokenOutputParse, error) {
+	return s.parseTokenWithoutValid(pToken, authService.VerificationKey)
+}
+
+/* Parse token, for which only expiration is not checked (signature and other claims must be valid) */
+func (s *TokenService) parseTokenWithoutValid(pToken string, keyFunc jwt.Keyfunc) (userModel.TokenOutputParse, error) {
+	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, keyFunc)
+
+	if err != nil {
+		validationErr, ok := err.(*jwt.ValidationError)
+		if !ok || validationErr.Errors != jwt.ValidationErrorExpired {
+			return userModel.TokenOutputParse{}, err
+		}
+	}
 
 	// Получение данных из токена (с преобразованием к указателю на tokenClaims)
 	claims, ok := token.Claims.(*tokenClaims)
@@ -107,9 +145,12 @@ func (s *TokenService) ParseTokenWithoutValid(pToken, signingKey string) (userMo
 	}
 
 	return userModel.TokenOutputParse{
-		UsersId:  user.Id,
-		AuthType: authType,
-		TokenApi: claims.TokenApi,
+		UsersId:     user.Id,
+		AuthType:    authType,
+		TokenApi:    claims.TokenApi,
+		DomainUuid:  claims.DomainsId,
+		SessionUuid: claims.SessionsId,
+		TokenId:     claims.Id,
 	}, nil
 }
 
@@ -127,7 +168,7 @@ func (s *TokenService) ParseResetToken(pToken, signingKey string) (userModel.Res
 			return nil, errors.New("invalid signing method")
 		}
 
-		return []byte(signingKey), nil
+		return authService.HmacKey(signingKey)
 	})
 
 	if !token.Valid {
@@ -161,3 +202,50 @@ func (s *TokenService) ParseResetToken(pToken, signingKey string) (userModel.Res
 		Email:   claims.Email,
 	}, nil
 }
+
+/* Structure body token of pending second factor */
+type tokenMfaClaims struct {
+	jwt.StandardClaims
+	UsersId   string `json:"users_id"`   // ID пользователя
+	DomainsId string `json:"domains_id"` // Домен, в котором выполняется вход
+}
+
+/* Parse token of pending second factor with validate check */
+func (s *TokenService) ParseMfaToken(pToken, signingKey string) (userModel.MfaTokenOutputParse, error) {
+	token, err := jwt.ParseWithClaims(pToken, &tokenMfaClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+
+		return authService.HmacKey(signingKey)
+	})
+
+	if err != nil {
+		return userModel.MfaTokenOutputParse{}, err
+	}
+
+	if !token.Valid {
+		return userModel.MfaTokenOutputParse{}, errors.New("token is not valid")
+	}
+
+	claims, ok := token.Claims.(*tokenMfaClaims)
+	if !ok {
+		return userModel.MfaTokenOutputParse{}, errors.New("token claims are not of type")
+	}
+
+	user, err := s.user.GetUser("uuid", claims.UsersId)
+
+	if err != nil {
+		return userModel.MfaTokenOutputParse{}, err
+	}
+
+	return userModel.MfaTokenOutputParse{
+		UsersId:    user.Id,
+		DomainUuid: claims.DomainsId,
+	}, nil
+}
+
+/* Getting public keys of access tokens signing */
+func (s *TokenService) GetJWKS() userModel.JWKSModel {
+	return authService.JWKS()
+}
@@ -44,8 +44,58 @@ func (s *UserService) GetArticle(uuid articleModel.ArticleUuidModel, c *gin.Cont
 }
 
 /* Get information about all article for user */
-func (s *UserService) GetArticles(c *gin.Context) (articleModel.ArticlesModel, error) {
-	return s.repo.GetArticles(c)
+func (s *UserService) GetArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error) {
+	return s.repo.GetArticles(c, filter)
+}
+
+/* Full-text search of articles for user */
+func (s *UserService) SearchArticles(c *gin.Context, search articleModel.ArticlesSearchRequestModel) (articleModel.ArticlesSearchModel, error) {
+	return s.repo.SearchArticles(c, search)
+}
+
+/* Get page of articles in trash */
+func (s *UserService) GetTrashArticles(c *gin.Context, filter articleModel.ArticlesFilterModel) (articleModel.ArticlesModel, error) {
+	return s.repo.GetTrashArticles(c, filter)
+}
+
+/* Restore article from trash */
+func (s *UserService) RestoreArticle(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	return s.repo.RestoreArticle(uuid, c)
+}
+
+/* Change status of article */
+func (s *UserService) UpdateArticleStatus(data articleModel.ArticleStatusRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	return s.repo.UpdateArticleStatus(data, c)
+}
+
+/* Grant access to article to another user */
+func (s *UserService) GrantArticleAccess(data articleModel.ArticleGrantRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	return s.repo.GrantArticleAccess(data, c)
+}
+
+/* Revoke access to article from user */
+func (s *UserService) RevokeArticleAccess(data articleModel.ArticleRevokeRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	return s.repo.RevokeArticleAccess(data, c)
+}
+
+/* Get list of users who have access to article */
+func (s *UserService) GetArticleGrants(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleGrantsModel, error) {
+	return s.repo.GetArticleGrants(uuid, c)
+}
+
+/* Get list of article revisions */
+func (s *UserService) GetArticleRevisions(uuid articleModel.ArticleUuidModel, c *gin.Context) (articleModel.ArticleRevisionsModel, error) {
+	return s.repo.GetArticleRevisions(uuid, c)
+}
+
+/* Get diff between two article revisions */
+func (s *UserService) GetArticleRevisionsDiff(data articleModel.ArticleRevisionDiffRequestModel, c *gin.Context) (articleModel.ArticleRevisionDiffModel, error) {
+	return s.repo.GetArticleRevisionsDiff(data, c)
+}
+
+/* Restore article from revision */
+func (s *UserService) RestoreArticleRevision(data articleModel.ArticleRevisionRestoreRequestModel, c *gin.Context) (articleModel.ArticleSuccessModel, error) {
+	return s.repo.RestoreArticleRevision(data, c)
 }
 
 /* ********** */
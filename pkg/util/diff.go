@@ -0,0 +1,159 @@
+package utils
+
+import "strings"
+
+const (
+	DIFF_EQUAL  = "equal"
+	DIFF_INSERT = "insert"
+	DIFF_DELETE = "delete"
+
+	// Maximum number of inserted and deleted lines, which are searched by diff
+	DIFF_MAX_EDITS = 1000
+)
+
+/* Line of text diff */
+type DiffLine struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+/* Line-based diff of two texts (shortest edit script) */
+func DiffLines(before, after string) []DiffLine {
+	a := splitLines(before)
+	b := splitLines(after)
+
+	// Общие начало и конец текстов не участвуют в поиске подпоследовательности
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]DiffLine, 0, len(a)+len(b))
+
+	for _, line := range a[:prefix] {
+		result = append(result, DiffLine{Type: DIFF_EQUAL, Text: line})
+	}
+
+	result = append(result, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, line := range a[len(a)-suffix:] {
+		result = append(result, DiffLine{Type: DIFF_EQUAL, Text: line})
+	}
+
+	return result
+}
+
+/*
+* Diff of changed part of texts (Myers algorithm, O((N+M)D) time)
+* (number of edits is limited, so that large revisions can not exhaust memory and time of server;
+* when limit is exceeded the changed part is shown as deleted and inserted entirely)
+ */
+func diffMiddle(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+
+	limit := DIFF_MAX_EDITS
+	if limit > n+m {
+		limit = n + m
+	}
+
+	// v[offset+k] - наибольший x на диагонали k = x - y, trace[d] - копия v после шага d
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	trace := make([][]int, 0, limit+1)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+				return diffBacktrack(a, b, trace)
+			}
+		}
+
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+
+	result := make([]DiffLine, 0, n+m)
+	for _, line := range a {
+		result = append(result, DiffLine{Type: DIFF_DELETE, Text: line})
+	}
+
+	for _, line := range b {
+		result = append(result, DiffLine{Type: DIFF_INSERT, Text: line})
+	}
+
+	return result
+}
+
+/* Restoring of edits by trace of Myers algorithm (from the end of texts) */
+func diffBacktrack(a, b []string, trace [][]int) []DiffLine {
+	result := make([]DiffLine, 0, len(a)+len(b))
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d > 0; d-- {
+		// Копия v после шага d - 1 содержит диагонали от -(d - 1) до d - 1
+		prev := trace[d-1]
+		k := x - y
+
+		prevK := k - 1
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			prevK = k + 1
+		}
+
+		prevX := prev[prevK+d-1]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			result = append(result, DiffLine{Type: DIFF_EQUAL, Text: a[x-1]})
+			x--
+			y--
+		}
+
+		if x == prevX {
+			result = append(result, DiffLine{Type: DIFF_INSERT, Text: b[prevY]})
+		} else {
+			result = append(result, DiffLine{Type: DIFF_DELETE, Text: a[prevX]})
+		}
+
+		x, y = prevX, prevY
+	}
+
+	for x > 0 && y > 0 {
+		result = append(result, DiffLine{Type: DIFF_EQUAL, Text: a[x-1]})
+		x--
+		y--
+	}
+
+	for left, right := 0, len(result)-1; left < right; left, right = left+1, right-1 {
+		result[left], result[right] = result[right], result[left]
+	}
+
+	return result
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
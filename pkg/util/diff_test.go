@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func equal(text string) DiffLine  { return DiffLine{Type: DIFF_EQUAL, Text: text} }
+func insert(text string) DiffLine { return DiffLine{Type: DIFF_INSERT, Text: text} }
+func remove(text string) DiffLine { return DiffLine{Type: DIFF_DELETE, Text: text} }
+
+/* Restoring of both texts from diff (every line of both texts must be present in diff in order) */
+func applyDiff(lines []DiffLine) (string, string) {
+	before, after := make([]string, 0), make([]string, 0)
+
+	for _, line := range lines {
+		if line.Type != DIFF_INSERT {
+			before = append(before, line.Text)
+		}
+
+		if line.Type != DIFF_DELETE {
+			after = append(after, line.Text)
+		}
+	}
+
+	return strings.Join(before, "\n"), strings.Join(after, "\n")
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []DiffLine
+	}{
+		{name: "both empty", before: "", after: "", want: []DiffLine{}},
+		{name: "from empty", before: "", after: "a\nb", want: []DiffLine{insert("a"), insert("b")}},
+		{name: "to empty", before: "a\nb", after: "", want: []DiffLine{remove("a"), remove("b")}},
+		{name: "equal", before: "a\nb", after: "a\nb", want: []DiffLine{equal("a"), equal("b")}},
+		{name: "windows line endings", before: "a\r\nb", after: "a\nb", want: []DiffLine{equal("a"), equal("b")}},
+		{name: "changed line", before: "a\nb\nc", after: "a\nx\nc", want: []DiffLine{equal("a"), remove("b"), insert("x"), equal("c")}},
+		{name: "inserted line", before: "a\nc", after: "a\nb\nc", want: []DiffLine{equal("a"), insert("b"), equal("c")}},
+		{name: "deleted line", before: "a\nb\nc", after: "a\nc", want: []DiffLine{equal("a"), remove("b"), equal("c")}},
+		{
+			name:   "moved line",
+			before: "a\nb\nc\nd",
+			after:  "b\nc\na\nd",
+			want:   []DiffLine{remove("a"), equal("b"), equal("c"), insert("a"), equal("d")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffLines(tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+/*
+* Number of edits is bounded: when limit is exceeded, changed part of texts is shown as deleted
+* and inserted entirely (common line in the middle of changed part is not found)
+ */
+func TestDiffLinesBounded(t *testing.T) {
+	text := func(prefix string, count int) string {
+		result := []string{"head"}
+		for i := 0; i < 2*count; i++ {
+			if i == count {
+				result = append(result, "middle")
+			}
+
+			result = append(result, fmt.Sprintf("%s %d", prefix, i))
+		}
+
+		return strings.Join(append(result, "tail"), "\n")
+	}
+
+	tests := []struct {
+		name       string
+		count      int // Количество изменённых строк до и после общей строки
+		wantLines  int
+		wantMiddle bool
+	}{
+		{name: "within limit", count: DIFF_MAX_EDITS / 4, wantLines: DIFF_MAX_EDITS + 3, wantMiddle: true},
+		{name: "over limit", count: DIFF_MAX_EDITS / 2, wantLines: 2*DIFF_MAX_EDITS + 4, wantMiddle: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := text("old", tt.count), text("new", tt.count)
+
+			got := DiffLines(before, after)
+
+			if len(got) != tt.wantLines {
+				t.Fatalf("DiffLines() returned %d lines, want %d", len(got), tt.wantLines)
+			}
+
+			if got[0] != equal("head") || got[len(got)-1] != equal("tail") {
+				t.Errorf("DiffLines() does not keep common head and tail: %v, %v", got[0], got[len(got)-1])
+			}
+
+			hasMiddle := false
+			for _, line := range got {
+				hasMiddle = hasMiddle || line == equal("middle")
+			}
+
+			if hasMiddle != tt.wantMiddle {
+				t.Errorf("DiffLines() keeps common middle line = %v, want %v", hasMiddle, tt.wantMiddle)
+			}
+
+			if gotBefore, gotAfter := applyDiff(got); gotBefore != before || gotAfter != after {
+				t.Errorf("DiffLines() does not restore texts")
+			}
+		})
+	}
+}
+
+/* Every line of both texts is kept in diff in its order */
+func TestDiffLinesRestoresTexts(t *testing.T) {
+	tests := []struct {
+		before string
+		after  string
+	}{
+		{before: "a\nb\nc\na\nb\nb\na", after: "c\nb\na\nb\na\nc"},
+		{before: "x\ny\nz", after: "z\ny\nx"},
+		{before: "one\n\ntwo", after: "\none\ntwo\n"},
+	}
+
+	for _, tt := range tests {
+		gotBefore, gotAfter := applyDiff(DiffLines(tt.before, tt.after))
+		if gotBefore != tt.before || gotAfter != tt.after {
+			t.Errorf("DiffLines(%q, %q) restores (%q, %q)", tt.before, tt.after, gotBefore, gotAfter)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS articles_checked_articles_id_idx;
+
+ALTER TABLE articles_checked DROP COLUMN IF EXISTS created_at;
+ALTER TABLE articles_checked DROP COLUMN IF EXISTS reason;
+ALTER TABLE articles_checked DROP COLUMN IF EXISTS is_approved;
+ALTER TABLE articles_checked DROP COLUMN IF EXISTS users_id;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS articles_checked
+(
+    id          SERIAL PRIMARY KEY,
+    articles_id INT NOT NULL REFERENCES articles (id) ON DELETE CASCADE
+);
+
+ALTER TABLE articles_checked ADD COLUMN IF NOT EXISTS users_id INT REFERENCES users (id) ON DELETE SET NULL;
+ALTER TABLE articles_checked ADD COLUMN IF NOT EXISTS is_approved BOOLEAN;
+ALTER TABLE articles_checked ADD COLUMN IF NOT EXISTS reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE articles_checked ADD COLUMN IF NOT EXISTS created_at TIMESTAMP;
+
+-- Previously an article was checked only by its approval, so existing rows are approvals
+-- (made not earlier than the last update of article, so that the article stays visible to guests)
+UPDATE articles_checked a1
+SET is_approved = true,
+    created_at  = coalesce(a2.updated_at, NOW())
+FROM articles a2
+WHERE a2.id = a1.articles_id
+  AND a1.is_approved IS NULL;
+
+UPDATE articles_checked
+SET created_at = NOW()
+WHERE created_at IS NULL;
+
+ALTER TABLE articles_checked ALTER COLUMN is_approved SET DEFAULT FALSE;
+ALTER TABLE articles_checked ALTER COLUMN is_approved SET NOT NULL;
+ALTER TABLE articles_checked ALTER COLUMN created_at SET DEFAULT NOW();
+ALTER TABLE articles_checked ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS articles_checked_articles_id_idx ON articles_checked (articles_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS articles_users_id_created_at_id_idx;
+DROP INDEX IF EXISTS articles_created_at_id_idx;
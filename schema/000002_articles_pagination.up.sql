@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS articles_created_at_id_idx ON articles (created_at, id);
+CREATE INDEX IF NOT EXISTS articles_users_id_created_at_id_idx ON articles (users_id, created_at, id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS articles_files_articles_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS articles_files_articles_id_idx ON articles_files (articles_id, index);
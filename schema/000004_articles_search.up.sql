@@ -0,0 +1,6 @@
+-- The expression must match articleSearchDocument in pkg/repository/article_search_postgres.go
+CREATE INDEX IF NOT EXISTS articles_search_idx ON articles USING GIN ((
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('russian', replace(coalesce(tags, ''), ';', ' ')), 'B') ||
+    setweight(to_tsvector('russian', coalesce(text, '')), 'C')
+));
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS tags
+(
+    id         SERIAL PRIMARY KEY,
+    value      VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS articles_tags
+(
+    articles_id INT NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    tags_id     INT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (articles_id, tags_id)
+);
+
+CREATE INDEX IF NOT EXISTS articles_tags_tags_id_idx ON articles_tags (tags_id);
+
+-- Splitting of existing free-form strings (normalized as in normalizeTag from pkg/repository/tag_postgres.go,
+-- legacy tags longer than the limit are truncated)
+INSERT INTO tags (value)
+SELECT DISTINCT left(lower(regexp_replace(trim(t), '\s+', ' ', 'g')), 64)
+FROM articles a,
+     unnest(string_to_array(a.tags, ';')) AS t
+WHERE trim(t) <> ''
+ON CONFLICT (value) DO NOTHING;
+
+INSERT INTO articles_tags (articles_id, tags_id)
+SELECT DISTINCT a.id, tg.id
+FROM articles a,
+     unnest(string_to_array(a.tags, ';')) AS t
+         JOIN tags tg ON tg.value = left(lower(regexp_replace(trim(t), '\s+', ' ', 'g')), 64)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS articles_revisions
+(
+    id          SERIAL PRIMARY KEY,
+    articles_id INT       NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    version     INT       NOT NULL,
+    users_id    INT       REFERENCES users (id) ON DELETE SET NULL,
+    title       TEXT      NOT NULL,
+    text        TEXT      NOT NULL,
+    tags        TEXT      NOT NULL,
+    filename    TEXT      NOT NULL,
+    filepath    TEXT      NOT NULL,
+    files       JSONB     NOT NULL DEFAULT '[]',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (articles_id, version)
+);
+
+-- The current state of every existing article becomes its first revision
+INSERT INTO articles_revisions (articles_id, version, users_id, title, text, tags, filename, filepath, files, created_at)
+SELECT a.id, 1, a.users_id, a.title, a.text, a.tags, a.filename, a.filepath,
+       coalesce((SELECT json_agg(json_build_object('index', af.index, 'filename', f.filename, 'filepath', f.filepath)
+                                 ORDER BY af.index)
+                 FROM articles_files af
+                          JOIN files f ON f.id = af.files_id
+                 WHERE af.articles_id = a.id), '[]'),
+       a.updated_at
+FROM articles a
+ON CONFLICT DO NOTHING;
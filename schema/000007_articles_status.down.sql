@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS articles_publish_at_idx;
+DROP INDEX IF EXISTS articles_status_idx;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS publish_at;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS status;
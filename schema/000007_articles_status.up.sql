@@ -0,0 +1,20 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'review'
+        CHECK (status IN ('draft', 'review', 'published', 'archived'));
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+
+-- Articles approved after their last update are already visible to guests
+UPDATE articles a1
+SET status = 'published'
+WHERE exists(SELECT *
+             FROM articles_checked a2
+             WHERE a2.articles_id = a1.id
+               AND a2.created_at >= a1.updated_at
+               AND a2.is_approved = true);
+
+CREATE INDEX IF NOT EXISTS articles_status_idx ON articles (status);
+
+-- Articles waiting for publishing by the scheduler
+CREATE INDEX IF NOT EXISTS articles_publish_at_idx ON articles (publish_at) WHERE status = 'review';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS articles_deleted_at_idx;
+
+-- Articles in trash are deleted permanently (their files remain on disk)
+DELETE FROM articles
+WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS deleted_at;
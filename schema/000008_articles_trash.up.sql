@@ -0,0 +1,5 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Articles in trash (listing of trash and purging after the retention period)
+CREATE INDEX IF NOT EXISTS articles_deleted_at_idx ON articles (deleted_at) WHERE deleted_at IS NOT NULL;
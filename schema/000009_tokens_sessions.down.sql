@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS tokens_users_id_idx;
+DROP INDEX IF EXISTS tokens_uuid_idx;
+
+-- Only the latest session of each user is kept
+DELETE FROM tokens t1
+WHERE EXISTS (SELECT 1 FROM tokens t2 WHERE t2.users_id = t1.users_id AND t2.id > t1.id);
+
+ALTER TABLE tokens
+    DROP COLUMN IF EXISTS last_seen_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS device,
+    DROP COLUMN IF EXISTS uuid;
@@ -0,0 +1,22 @@
+-- Each sign-in creates its own session (row of tokens table) with information about the device
+ALTER TABLE tokens
+    ADD COLUMN IF NOT EXISTS uuid UUID,
+    ADD COLUMN IF NOT EXISTS device VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS user_agent TEXT,
+    ADD COLUMN IF NOT EXISTS ip VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP;
+
+UPDATE tokens
+SET uuid         = md5(random()::text || id::text)::uuid,
+    created_at   = now(),
+    last_seen_at = now()
+WHERE uuid IS NULL;
+
+ALTER TABLE tokens
+    ALTER COLUMN uuid SET NOT NULL,
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN last_seen_at SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tokens_uuid_idx ON tokens (uuid);
+CREATE INDEX IF NOT EXISTS tokens_users_id_idx ON tokens (users_id);
@@ -0,0 +1,11 @@
+-- Refresh tokens already exchanged for new ones (reuse of such token revokes the whole session)
+CREATE TABLE IF NOT EXISTS tokens_used
+(
+    id         SERIAL PRIMARY KEY,
+    tokens_id  INTEGER     NOT NULL REFERENCES tokens (id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    used_at    TIMESTAMP   NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tokens_used_token_hash_idx ON tokens_used (token_hash);
+CREATE INDEX IF NOT EXISTS tokens_used_tokens_id_idx ON tokens_used (tokens_id);
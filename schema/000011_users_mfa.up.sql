@@ -0,0 +1,22 @@
+-- TOTP secrets of users (two-factor authentication is enabled after confirmation with the first code)
+CREATE TABLE IF NOT EXISTS users_totp
+(
+    id           SERIAL PRIMARY KEY,
+    users_id     INTEGER     NOT NULL UNIQUE REFERENCES users (id) ON DELETE CASCADE,
+    secret       VARCHAR(64) NOT NULL,
+    is_enabled   BOOLEAN     NOT NULL DEFAULT false,
+    last_step    BIGINT      NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP   NOT NULL,
+    confirmed_at TIMESTAMP
+);
+
+-- One-time recovery codes (only hashes are stored)
+CREATE TABLE IF NOT EXISTS users_recovery_codes
+(
+    id        SERIAL PRIMARY KEY,
+    users_id  INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at   TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS users_recovery_codes_users_id_idx ON users_recovery_codes (users_id);
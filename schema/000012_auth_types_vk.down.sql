@@ -0,0 +1,2 @@
+DELETE FROM users_auth_types WHERE auth_types_id IN (SELECT id FROM auth_types WHERE value = 'vk');
+DELETE FROM auth_types WHERE value = 'vk';
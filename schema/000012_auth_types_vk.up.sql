@@ -0,0 +1,4 @@
+-- Authentication type for sign-in through VK
+INSERT INTO auth_types (uuid, value)
+SELECT md5(random()::text || clock_timestamp()::text)::uuid, 'vk'
+WHERE NOT EXISTS (SELECT 1 FROM auth_types WHERE value = 'vk');
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS tokens_auth_types_id_idx;
+
+ALTER TABLE tokens
+    DROP COLUMN IF EXISTS auth_types_id;
+
+DROP INDEX IF EXISTS users_auth_types_subject_idx;
+
+ALTER TABLE users_auth_types
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS subject;
@@ -0,0 +1,14 @@
+-- Each linked login method keeps identifier of user at external provider
+ALTER TABLE users_auth_types
+    ADD COLUMN IF NOT EXISTS subject VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS email VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT now();
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_auth_types_subject_idx ON users_auth_types (auth_types_id, subject)
+    WHERE subject IS NOT NULL;
+
+-- Session remembers login method, by which it was created (sessions are revoked on unlink)
+ALTER TABLE tokens
+    ADD COLUMN IF NOT EXISTS auth_types_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS tokens_auth_types_id_idx ON tokens (users_id, auth_types_id);
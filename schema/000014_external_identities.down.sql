@@ -0,0 +1,19 @@
+UPDATE users
+SET password = ''
+WHERE password IS NULL;
+
+ALTER TABLE users_auth_types
+    ADD COLUMN IF NOT EXISTS subject VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS email VARCHAR(255);
+
+UPDATE users_auth_types td
+SET subject = ei.subject,
+    email   = ei.email
+FROM external_identities ei
+WHERE ei.users_id = td.users_id
+  AND ei.auth_types_id = td.auth_types_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_auth_types_subject_idx ON users_auth_types (auth_types_id, subject)
+    WHERE subject IS NOT NULL;
+
+DROP TABLE IF EXISTS external_identities;
@@ -0,0 +1,40 @@
+-- Accounts of external providers linked to users (tokens of providers are stored encrypted)
+CREATE TABLE IF NOT EXISTS external_identities
+(
+    id            SERIAL PRIMARY KEY,
+    users_id      INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    auth_types_id INTEGER      NOT NULL,
+    subject       VARCHAR(255) NOT NULL,
+    email         VARCHAR(255),
+    access_token  TEXT,
+    refresh_token TEXT,
+    expires_at    TIMESTAMP,
+    created_at    TIMESTAMP    NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMP    NOT NULL DEFAULT now(),
+    UNIQUE (auth_types_id, subject),
+    UNIQUE (users_id, auth_types_id)
+);
+
+INSERT INTO external_identities (users_id, auth_types_id, subject, email, created_at, updated_at)
+SELECT users_id, auth_types_id, subject, email, created_at, created_at
+FROM users_auth_types
+WHERE subject IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+DROP INDEX IF EXISTS users_auth_types_subject_idx;
+
+ALTER TABLE users_auth_types
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS subject;
+
+-- Users without local login method have no password (previously it held token of provider)
+ALTER TABLE users
+    ALTER COLUMN password DROP NOT NULL;
+
+UPDATE users u
+SET password = NULL
+WHERE NOT EXISTS (SELECT 1
+                  FROM users_auth_types td
+                           INNER JOIN auth_types tl ON tl.id = td.auth_types_id
+                  WHERE td.users_id = u.id
+                    AND tl.value = 'local');
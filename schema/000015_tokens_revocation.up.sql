@@ -0,0 +1,12 @@
+-- Version of access tokens of user (increment revokes all access tokens issued before)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+
+-- Revoked access tokens (jti), which are kept only until expiration of token
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti        VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP   NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS revoked_tokens_expires_at_idx ON revoked_tokens (expires_at);
@@ -0,0 +1,17 @@
+-- Failed attempts of sign-in and requests of password recovery (per account and per IP address)
+CREATE TABLE IF NOT EXISTS auth_attempts
+(
+    id                SERIAL PRIMARY KEY,
+    scope             VARCHAR(32)  NOT NULL,
+    kind              VARCHAR(32)  NOT NULL,
+    value             VARCHAR(255) NOT NULL,
+    failures          INTEGER      NOT NULL DEFAULT 0,
+    last_failure_at   TIMESTAMP    NOT NULL,
+    locked_until      TIMESTAMP,
+    unlock_token_hash VARCHAR(64)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS auth_attempts_key_idx ON auth_attempts (scope, kind, value);
+CREATE UNIQUE INDEX IF NOT EXISTS auth_attempts_unlock_token_hash_idx ON auth_attempts (unlock_token_hash)
+    WHERE unlock_token_hash IS NOT NULL;
+CREATE INDEX IF NOT EXISTS auth_attempts_last_failure_at_idx ON auth_attempts (last_failure_at);
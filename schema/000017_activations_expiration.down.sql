@@ -0,0 +1,2 @@
+ALTER TABLE activations
+    DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,9 @@
+-- Activation links expire (a new link can be requested again)
+ALTER TABLE activations
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+-- Links issued before are valid for a day since migration
+UPDATE activations
+SET expires_at = now() + interval '1 day'
+WHERE is_activated = false
+  AND expires_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS casbin_rules_notify ON misu_rules;
+DROP FUNCTION IF EXISTS casbin_rules_notify();
@@ -0,0 +1,31 @@
+-- Rules table of casbin (name of table must match rules_table_name of configuration)
+CREATE TABLE IF NOT EXISTS misu_rules
+(
+    id    BIGSERIAL PRIMARY KEY,
+    ptype VARCHAR(100),
+    v0    VARCHAR(255),
+    v1    VARCHAR(255),
+    v2    VARCHAR(255),
+    v3    VARCHAR(255),
+    v4    VARCHAR(255),
+    v5    VARCHAR(255),
+    v6    VARCHAR(255),
+    v7    VARCHAR(255)
+);
+
+-- Notification of all server replicas about any change of policy (Postgres LISTEN/NOTIFY)
+CREATE OR REPLACE FUNCTION casbin_rules_notify() RETURNS trigger AS
+$$
+BEGIN
+    PERFORM pg_notify('casbin_rules', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS casbin_rules_notify ON misu_rules;
+
+CREATE TRIGGER casbin_rules_notify
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE
+    ON misu_rules
+    FOR EACH STATEMENT
+EXECUTE PROCEDURE casbin_rules_notify();
@@ -0,0 +1,7 @@
+-- Encrypted secrets do not fit the previous column (second factor of these users has to be enrolled again)
+DELETE
+FROM users_totp
+WHERE length(secret) > 64;
+
+ALTER TABLE users_totp
+    ALTER COLUMN secret TYPE VARCHAR(64);
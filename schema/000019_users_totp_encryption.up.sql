@@ -0,0 +1,3 @@
+-- TOTP secrets are stored encrypted (encrypted value is longer than secret)
+ALTER TABLE users_totp
+    ALTER COLUMN secret TYPE VARCHAR(255);
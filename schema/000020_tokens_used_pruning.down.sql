@@ -0,0 +1 @@
+DROP INDEX IF EXISTS tokens_used_used_at_idx;
@@ -0,0 +1,2 @@
+-- Used refresh tokens are removed after expiration of refresh token
+CREATE INDEX IF NOT EXISTS tokens_used_used_at_idx ON tokens_used (used_at);
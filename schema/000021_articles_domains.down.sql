@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS articles_domains_id_idx;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS domains_id;
@@ -0,0 +1,22 @@
+-- Each article belongs to the domain, in which it has been created
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS domains_id INTEGER REFERENCES domains (id) ON DELETE CASCADE;
+
+-- Domain of existing article is taken from access policies of its author (v0 - user, v1 - domain, v2 - article)
+UPDATE articles a1
+SET domains_id = (SELECT min(r.v1::INTEGER)
+                  FROM misu_rules r
+                  WHERE r.ptype = 'p'
+                    AND r.v0 = a1.users_id::VARCHAR
+                    AND r.v2 = a1.uuid::VARCHAR)
+WHERE a1.domains_id IS NULL;
+
+-- Articles without policies of author belong to the first domain
+UPDATE articles
+SET domains_id = (SELECT min(id) FROM domains)
+WHERE domains_id IS NULL;
+
+ALTER TABLE articles
+    ALTER COLUMN domains_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS articles_domains_id_idx ON articles (domains_id);